@@ -0,0 +1,216 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+
+	"github.com/Optum/cloudig/pkg/cloudig"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/kris-nova/logger"
+	"github.com/spf13/cobra"
+)
+
+var (
+	scaffoldReportNames string
+	lintReportNames     string
+	lintReportFile      string
+)
+
+// commentsCmd represents the comments command
+var commentsCmd = &cobra.Command{
+	Use:   "comments",
+	Short: "Manage the comments file",
+	Args:  cobra.OnlyValidArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if len(args) < 1 {
+			fmt.Println("missing subcommands")
+			os.Exit(exitCodeError)
+		}
+	},
+}
+
+// scaffoldCmd represents the comments scaffold command
+var scaffoldCmd = &cobra.Command{
+	Use:   "scaffold",
+	Short: "Add the keys of the findings without comment to the comments file, with empty comments",
+	Long: `Runs the reports and appends the key of every NEW_FINDING to the comments file given with --cfile with an empty
+comment, as a new entry per account. The existing content is kept as is, the file is created when it doesn't exist.
+With several --cfile, the last one is written and has to be a local file`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		reportTypes := getCommentsReportTypes(scaffoldReportNames)
+		commentsFile := commentsFiles[len(commentsFiles)-1]
+		if cloudig.IsCommentsURL(commentsFile) {
+			logger.Critical("comments scaffold writes the last --cfile, %s is not a local file", commentsFile)
+			os.Exit(exitCodeError)
+		}
+		content, err := ioutil.ReadFile(commentsFile)
+		if err != nil && !os.IsNotExist(err) {
+			logger.Critical("error reading file %s: %v", commentsFile, err)
+			os.Exit(exitCodeError)
+		}
+		keys := collectCommentKeys(newSession(), reportTypes)
+
+		content, added, err := cloudig.ScaffoldComments(content, keys)
+		if err != nil {
+			logger.Critical("error scaffolding file %s: %v", commentsFile, err)
+			os.Exit(exitCodeError)
+		}
+		if added == 0 {
+			logger.Success("every finding has a comment in file %s", commentsFile)
+			return
+		}
+		err = ioutil.WriteFile(commentsFile, content, 0644)
+		if err != nil {
+			logger.Critical("error writing file %s: %v", commentsFile, err)
+			os.Exit(exitCodeError)
+		}
+		logger.Success("added %d finding keys to file %s", added, commentsFile)
+	},
+}
+
+// lintCmd represents the comments lint command
+var lintCmd = &cobra.Command{
+	Use:   "lint",
+	Short: "List the issues of the comments file, like the comment keys matching no finding",
+	Long: `Lists the issues of the comments files given with --cfile: YAML parse errors, invalid accounts, unknown sections,
+keys with the format of another section or of no section, keys commented twice for an account, and keys matching none
+of the findings of the reports. The findings are those of the reports run by the command, or of the JSON report given
+with --report-file. Exits with code 2 when there are issues`,
+
+	Run: func(cmd *cobra.Command, args []string) {
+		sess := newSession()
+		var keys *cloudig.CommentKeys
+		var sections []string
+		if lintReportFile != "" {
+			reportContent, err := ioutil.ReadFile(lintReportFile)
+			if err != nil {
+				logger.Critical("error reading file %s: %v", lintReportFile, err)
+				os.Exit(exitCodeError)
+			}
+			report, err := cloudig.ParseReportJSON(reportContent, lintReportNames)
+			if err != nil {
+				logger.Critical("error reading file %s: %v", lintReportFile, err)
+				os.Exit(exitCodeError)
+			}
+			keys = cloudig.NewCommentKeys()
+			keys.Record(report)
+			keys.AddReportAccounts(report)
+			sections = cloudig.GetCommentsKeys(report)
+		} else {
+			reportTypes := getCommentsReportTypes(lintReportNames)
+			keys = collectCommentKeys(sess, reportTypes)
+			for _, reportType := range reportTypes {
+				sections = append(sections, reportType.CommentsKey)
+			}
+		}
+
+		issues := make([]cloudig.LintIssue, 0)
+		for _, commentsFile := range commentsFiles {
+			content, err := cloudig.ReadCommentsFile(sess, commentsFile)
+			if err != nil {
+				logger.Critical("%v", err)
+				os.Exit(exitCodeError)
+			}
+			for _, issue := range cloudig.LintComments(content, keys, sections) {
+				issue.File = commentsFile
+				issues = append(issues, issue)
+			}
+		}
+		fmt.Println(cloudig.RenderLintIssues(issues, output))
+		if len(issues) != 0 {
+			logger.Critical("found %d issues in the comments files", len(issues))
+			os.Exit(exitCodeFailOn)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commentsCmd)
+	commentsCmd.AddCommand(scaffoldCmd)
+	commentsCmd.AddCommand(lintCmd)
+	for _, cmd := range commentsCmd.Commands() {
+		commentsCmd.ValidArgs = append(commentsCmd.ValidArgs, cmd.Name())
+	}
+	commentsCmd.Use = commentsCmd.Use + " " + strings.Join(commentsCmd.ValidArgs, "/")
+
+	scaffoldCmd.PersistentFlags().StringVar(&scaffoldReportNames, "reports", "", "One or more reports separated by a comma [,] to scaffold the comments of. Options: ["+strings.Join(getCommentsReportNames(), ", ")+"] or their aliases. Default is every get report")
+	// lintCmd specific flags
+	lintCmd.PersistentFlags().StringVar(&lintReportNames, "reports", "", "One or more reports separated by a comma [,] to lint the comments of. Options: ["+strings.Join(getCommentsReportNames(), ", ")+"] or their aliases. Default is every get report. Names the report of the --report-file JSON when it is not the output of get all")
+	lintCmd.PersistentFlags().StringVar(&lintReportFile, "report-file", "", "JSON output of a report to lint the comments against instead of running the reports")
+}
+
+// getCommentsReportTypes returns the report types with the given names, every get report when names is empty
+func getCommentsReportTypes(names string) []cloudig.ReportType {
+	reportTypes := make([]cloudig.ReportType, 0)
+	for _, name := range getReportNames(names) {
+		reportType, ok := cloudig.GetReportType("", name)
+		if !ok || reportType.CommentsKey == "" {
+			logger.Critical("unknown report '%s'. Options: [%s] or their aliases", name, strings.Join(getCommentsReportNames(), ", "))
+			os.Exit(exitCodeError)
+		}
+		reportTypes = append(reportTypes, reportType)
+	}
+	return reportTypes
+}
+
+// collectCommentKeys runs the reports of the report types against the accounts and returns the comment keys of
+// their findings
+func collectCommentKeys(sess *session.Session, reportTypes []cloudig.ReportType) *cloudig.CommentKeys {
+	reports := make([]cloudig.Report, 0, len(reportTypes))
+	for _, reportType := range reportTypes {
+		report, err := reportType.New(getReportOptions(reportType, nil))
+		if err != nil {
+			logger.Critical("%v", err)
+			os.Exit(exitCodeError)
+		}
+		reports = append(reports, report)
+	}
+
+	assumeRoleOptions, err := getAssumeRoleOptions()
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
+	}
+	regionList, err := cloudig.ResolveRegions(regions, region)
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
+	}
+	accounts := []string{"parent"}
+	if orgMode {
+		accounts = getOrganizationAccounts(sess)
+	} else if roleARN != "" {
+		accounts = strings.Split(roleARN, ",")
+	}
+
+	comments, err := cloudig.LoadComments(sess, commentsFiles)
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
+	}
+	report := &cloudig.CompositeReport{Reports: reports}
+	reportErrors := cloudig.CollectReport(sess, report, comments, accounts, regionList, maxConcurrency, assumeRoleOptions)
+	for _, e := range reportErrors {
+		logger.Warning("the findings of report '%s' for %s are missing", e.Report, e.Error())
+	}
+	keys := cloudig.NewCommentKeys()
+	keys.Record(report)
+	// the keys of the accounts with missing findings can't be told stale
+	keys.AddCollectedAccounts(cloudig.GetCollectedAccountIDs(sess, accounts, reportErrors)...)
+	return keys
+}
+
+// getCommentsReportNames returns the names of the report types with comments
+func getCommentsReportNames() []string {
+	names := make([]string, 0)
+	for _, reportType := range cloudig.GetReportTypes("") {
+		if reportType.CommentsKey != "" {
+			names = append(names, reportType.Name)
+		}
+	}
+	return names
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/Optum/cloudig/pkg/cloudig"
+
+	"github.com/kris-nova/logger"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var (
+	configFile     string
+	profileSetName string
+	// config is the content of the config file, empty when there is none
+	config cloudig.Config
+	// reportCmds holds the subcommand of each report type by report name
+	reportCmds = make(map[string]*cobra.Command)
+)
+
+// applyProfileSet loads the config file and sets the flags that were not given on the command line from the selected profile set of the config
+// file. The default profile set is used when none is selected and the config file defines one
+func applyProfileSet(cmd *cobra.Command) {
+	path := configFile
+	if path == "" {
+		path = cloudig.DefaultConfigFile()
+		if _, err := os.Stat(path); err != nil {
+			if profileSetName != "" {
+				logger.Critical("--profile-set '%s' requires a config file, none found at '%s'", profileSetName, path)
+				os.Exit(exitCodeError)
+			}
+			return
+		}
+	}
+	var err error
+	config, err = cloudig.LoadConfig(path)
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
+	}
+	name := profileSetName
+	if name == "" {
+		if _, ok := config.ProfileSets[cloudig.DefaultProfileSet]; !ok {
+			return
+		}
+		name = cloudig.DefaultProfileSet
+	}
+	profileSet, err := config.GetProfileSet(name)
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
+	}
+	logger.Debug("using profile set '%s' of config file '%s'", name, path)
+
+	// a profile set can hold the flags of every command, ex: the reports of get all, only those of cmd are set
+	otherFlags := getFlagNames(cmd.Root())
+	err = setFlags(cmd.Flags(), profileSet.Flags(), otherFlags)
+	if err == nil {
+		// the flags of every report are set so that the reports of get all are configured as well
+		for _, reportType := range cloudig.GetReportTypes("") {
+			err = setFlags(reportCmds[reportType.Name].PersistentFlags(), profileSet.ReportFlags(reportType), nil)
+			if err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		logger.Critical("error applying profile set '%s': %v", name, err)
+		os.Exit(exitCodeError)
+	}
+}
+
+// getFlagNames returns the names of the flags of a command and of its subcommands
+func getFlagNames(cmd *cobra.Command) []string {
+	names := make([]string, 0)
+	addName := func(flag *pflag.Flag) {
+		if !cloudig.Contains(names, flag.Name) {
+			names = append(names, flag.Name)
+		}
+	}
+	var visit func(c *cobra.Command)
+	visit = func(c *cobra.Command) {
+		c.Flags().VisitAll(addName)
+		c.PersistentFlags().VisitAll(addName)
+		for _, subCmd := range c.Commands() {
+			visit(subCmd)
+		}
+	}
+	visit(cmd)
+	return names
+}
+
+// setFlags sets the flags that were not explicitly given on the command line to the values, so that explicit flags
+// override the profile set. The values of otherFlags, the flags of the other commands, are skipped when flags doesn't
+// define them, so that a profile set can be shared by the commands
+func setFlags(flags *pflag.FlagSet, values map[string]string, otherFlags []string) error {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		flag := flags.Lookup(name)
+		if flag == nil && cloudig.Contains(otherFlags, name) {
+			continue
+		}
+		if flag == nil {
+			return fmt.Errorf("unknown flag '%s' in profile set", name)
+		}
+		if flag.Changed {
+			continue
+		}
+		err := flag.Value.Set(values[name])
+		if err != nil {
+			return fmt.Errorf("invalid value '%s' for flag '%s' in profile set: %v", values[name], name, err)
+		}
+	}
+	return nil
+}
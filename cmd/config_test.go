@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFlags(t *testing.T) {
+	var output string
+	var maxConcurrency int
+	var excludeRegions []string
+	flags := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	flags.StringVar(&output, "output", "json", "")
+	flags.IntVar(&maxConcurrency, "max-concurrency", 10, "")
+	flags.StringSliceVar(&excludeRegions, "exclude-regions", []string{}, "")
+	assert.NoError(t, flags.Parse([]string{"--output", "mdtable"}))
+
+	err := setFlags(flags, map[string]string{"output": "table", "max-concurrency": "5", "exclude-regions": "us-west-1,us-west-2"}, nil)
+	assert.NoError(t, err)
+	// explicit flags override the profile set
+	assert.Equal(t, "mdtable", output)
+	assert.Equal(t, 5, maxConcurrency)
+	assert.Equal(t, []string{"us-west-1", "us-west-2"}, excludeRegions)
+
+	err = setFlags(flags, map[string]string{"unknown": "value"}, []string{"reports"})
+	assert.Equal(t, errors.New("unknown flag 'unknown' in profile set"), err)
+
+	err = setFlags(flags, map[string]string{"max-concurrency": "many"}, nil)
+	assert.Error(t, err)
+
+	// the flags of the other commands are skipped, ex: the reports of get all when running get trustedadvisor
+	err = setFlags(flags, map[string]string{"reports": "ta,config", "output": "csv"}, []string{"reports", "report-file"})
+	assert.NoError(t, err)
+	assert.Equal(t, "mdtable", output)
+	assert.Nil(t, flags.Lookup("reports"))
+}
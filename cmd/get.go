@@ -9,199 +9,95 @@ import (
 	awslocal "github.com/Optum/cloudig/pkg/aws"
 	"github.com/Optum/cloudig/pkg/cloudig"
 
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/fatih/color"
 	"github.com/kris-nova/logger"
+	"github.com/mattn/go-colorable"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var (
-	commentsFile          string
-	roleARN               string
-	output                string
-	region                string
-	pastDays              string
-	healthExcludeRegions  string
-	healthIncludeRegions  string
-	details               bool
-	ecrImageTag           string
-	identityARNs          string
-	identityTags          string
-	includeUsage          bool
-	includeErrors         bool
-	includeCallerIdentity bool
-	absoluteTime          string
-	relativeTime          int
+	commentsFiles  []string
+	roleARN        string
+	output         string
+	outFile        string
+	outS3          string
+	splitByAccount bool
+	sortBy         []string
+	groupBy        string
+	region         string
+	regions        string
+	maxConcurrency int
+	orgMode        bool
+	orgUnits       string
+	orgTags        string
+	assumeRoleName string
+	reportNames    string
+	failOn         string
+	profile        string
+	externalID     string
+	externalIDFile string
+	sessionName    string
+	duration       time.Duration
+	mfaSerial      string
+	viaRoles       string
+)
+
+// Exit codes of the get and reflect commands, so that pipelines can tell findings over the fail-on threshold
+// apart from errors
+const (
+	exitCodeError  = 1
+	exitCodeFailOn = 2
 )
 
 // getCmd represents the get command
 var getCmd = &cobra.Command{
-	Use:       "get trustedadvisor/awsconfig/inspector/health/ecrscan",
-	Short:     "Get report findings",
-	ValidArgs: []string{"trustedadvisor", "awsconfig", "inspector", "health", "ecrscan"},
-	Args:      cobra.OnlyValidArgs,
+	Use:   "get",
+	Short: "Get report findings",
+	Args:  cobra.OnlyValidArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) < 1 {
 			fmt.Println("missing subcommands")
-			os.Exit(1)
+			os.Exit(exitCodeError)
 		}
 	},
 }
 
 // reflectCmd represents the reflect command
 var reflectCmd = &cobra.Command{
-	Use:       "reflect iam",
-	Short:     "Reflect on IAM role permissions",
-	ValidArgs: []string{"iam"},
-	Args:      cobra.OnlyValidArgs,
+	Use:   "reflect",
+	Short: "Reflect on IAM role permissions",
+	Args:  cobra.OnlyValidArgs,
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) < 1 {
 			fmt.Println("missing subcommands")
-			os.Exit(1)
-		}
-	},
-}
-
-// trustedAdvisorCmd represents the get trustedadvisor command
-var trustedAdvisorCmd = &cobra.Command{
-	Use:     "trustedadvisor",
-	Short:   "Get AWS Trusted Advisor report findings",
-	Aliases: []string{"ta", "t"},
-
-	Run: func(cmd *cobra.Command, args []string) {
-		execute(&cloudig.TrustedAdvisorReport{})
-	},
-}
-
-// awsConfigCmd represents the config command
-var awsConfigCmd = &cobra.Command{
-	Use:     "awsconfig",
-	Short:   "Get AWS Config report findings",
-	Aliases: []string{"ac", "a"},
-
-	Run: func(cmd *cobra.Command, args []string) {
-		execute(&cloudig.ConfigReport{})
-	},
-}
-
-// inspectorCmd represents the inspector command
-var inspectorCmd = &cobra.Command{
-	Use:     "inspector",
-	Short:   "Get AWS Inspector report findings",
-	Aliases: []string{"inspect", "ins", "i"},
-
-	Run: func(cmd *cobra.Command, args []string) {
-		execute(&cloudig.InspectorReports{Helper: &cloudig.InspectorHelper{}})
-	},
-}
-
-// healthCmd represents the get health command
-var healthCmd = &cobra.Command{
-	Use:     "health",
-	Short:   "Get AWS Health notifications' details",
-	Aliases: []string{"he", "h", "healthnotifications", "healthnotification"},
-
-	Run: func(cmd *cobra.Command, args []string) {
-		excludeRegionsArr := strings.Split(healthExcludeRegions, ",")
-		if healthExcludeRegions == "" {
-			excludeRegionsArr = []string{}
+			os.Exit(exitCodeError)
 		}
-		includeRegionsArr := strings.Split(healthIncludeRegions, ",")
-		if healthIncludeRegions == "" {
-			includeRegionsArr = []string{}
-		}
-		flags := struct {
-			Details        bool
-			PastDays       string
-			ExcludeRegions []string
-			IncludeRegions []string
-		}{
-			Details:        details,
-			PastDays:       pastDays,
-			ExcludeRegions: excludeRegionsArr,
-			IncludeRegions: includeRegionsArr,
-		}
-
-		execute(&cloudig.HealthReport{
-			Flags: flags,
-		})
 	},
 }
 
-// ecrScanCmd represents the ecrscan command
-var ecrScanCmd = &cobra.Command{
-	Use:     "ecrscan",
-	Short:   "Get ECR Image Scan report findings",
-	Aliases: []string{"scan", "sc", "s"},
-	Run: func(cmd *cobra.Command, args []string) {
-		execute(&cloudig.ImageScanReports{
-			Flags: cloudig.ImageScanReportFlags{
-				Tag:    ecrImageTag,
-				Region: region,
-			},
-		})
-	},
-}
-
-// iamCmd represents the reflect IAM command
-var iamCmd = &cobra.Command{
-	Use:     "iam",
-	Short:   "Reflect on IAM Role permissions",
-	Aliases: []string{"i", "iamrole"},
+// allCmd represents the get all command
+var allCmd = &cobra.Command{
+	Use:   "all",
+	Short: "Get the findings of several reports as one document",
 
 	Run: func(cmd *cobra.Command, args []string) {
-
-		var roles []string
-		if identityARNs != "" {
-			roles = strings.Split(identityARNs, ",")
-		}
-
-		// convert string "k1:v1,k2:v2" to map[string]string{"k1":"v1","k2":"v2"}
-		tags := make(map[string]string)
-		for _, v := range strings.Split(identityTags, ",") {
-			kv := strings.Split(v, ":")
-			if len(kv) == 2 {
-				tags[kv[0]] = kv[1]
-			}
-		}
-
-		// if -u or -e is not provided both will be true, if one of them is provided then the other one is false
-		if !includeUsage && !includeErrors {
-			includeUsage = true
-			includeErrors = true
-		}
-
-		// validate absolute-time
-		if absoluteTime != "" {
-			var startTime, endTime time.Time
-			errorMessage := "--absolute-time is wrong. It should be in the form 'startTime-endTime' 'mm/dd/yyyy-mm/dd/yyy' ex: '10/25/2020-10/31/2020'"
-			dates := strings.Split(absoluteTime, "-")
-			if len(dates) != 2 {
-				fmt.Println(errorMessage)
-				os.Exit(1)
-			}
-			startDate, endDate := dates[0], dates[1]
-			format := "01/02/2006" // mm/dd/yyyy format
-			var err error
-			// convert to time
-			startTime, err = time.Parse(format, startDate)
-			if err != nil {
-				fmt.Println(errorMessage)
-				os.Exit(1)
+		reports := make([]cloudig.Report, 0)
+		for _, name := range getReportNames(reportNames) {
+			reportType, ok := cloudig.GetReportType(cloudig.CommandGet, name)
+			if !ok {
+				logger.Critical("unknown report '%s'. Options: [%s] or their aliases", name, strings.Join(getReportNames(""), ", "))
+				os.Exit(exitCodeError)
 			}
-			endTime, err = time.Parse(format, endDate)
+			report, err := reportType.New(getReportOptions(reportType, cmd.PersistentFlags()))
 			if err != nil {
-				fmt.Println(errorMessage)
-				os.Exit(1)
-			}
-			if endTime.Before(startTime) {
-				fmt.Println(errorMessage)
-				os.Exit(1)
+				logger.Critical("%v", err)
+				os.Exit(exitCodeError)
 			}
+			reports = append(reports, report)
 		}
-
-		execute(&cloudig.ReflectReport{
-			Flags: cloudig.NewReflectFlags(region, roles, tags, includeUsage, includeErrors, includeCallerIdentity, absoluteTime, relativeTime),
-		})
-
+		execute(&cloudig.CompositeReport{Reports: reports})
 	},
 }
 
@@ -209,61 +105,255 @@ func init() {
 	rootCmd.AddCommand(getCmd)
 	rootCmd.AddCommand(reflectCmd)
 
-	getCmd.AddCommand(trustedAdvisorCmd)
-	getCmd.AddCommand(awsConfigCmd)
-	getCmd.AddCommand(inspectorCmd)
-	getCmd.AddCommand(healthCmd)
-	getCmd.AddCommand(ecrScanCmd)
-	reflectCmd.AddCommand(iamCmd)
+	// generate the subcommands of the registered report types
+	for _, reportType := range cloudig.GetReportTypes("") {
+		parentCmd := getCmd
+		if reportType.Command == cloudig.CommandReflect {
+			parentCmd = reflectCmd
+		}
+		reportCmds[reportType.Name] = newReportCmd(reportType)
+		parentCmd.AddCommand(reportCmds[reportType.Name])
+	}
+	getCmd.AddCommand(allCmd)
+	// the options of the reports can be given to get all as well, without shorthand as they could clash
+	for _, reportType := range cloudig.GetReportTypes(cloudig.CommandGet) {
+		addAllOptionFlags(allCmd.PersistentFlags(), reportType)
+	}
+	for _, parentCmd := range []*cobra.Command{getCmd, reflectCmd} {
+		for _, cmd := range parentCmd.Commands() {
+			parentCmd.ValidArgs = append(parentCmd.ValidArgs, cmd.Name())
+		}
+		parentCmd.Use = parentCmd.Use + " " + strings.Join(parentCmd.ValidArgs, "/")
+	}
 
 	// Here you will define your flags and configuration settings.
-	rootCmd.PersistentFlags().StringVarP(&commentsFile, "cfile", "c", "comments.yaml", "Comments file name")
+	rootCmd.PersistentFlags().StringSliceVarP(&commentsFiles, "cfile", "c", []string{"comments.yaml"}, "Comments file name, s3://bucket/key or https:// URL. Repeat the flag or separate the files with a comma [,] to layer several files, later files override the comments of earlier files per account and key")
 	rootCmd.PersistentFlags().StringVar(&roleARN, "rolearn", "", "One or more role ARNs seperated by a comma [,]")
-	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "json", "Output of report. Options: [json, table, mdtable]. Default output is JSON")
+	rootCmd.PersistentFlags().StringVarP(&output, "output", "o", "json", "Output of report. Options: [json, table, mdtable, csv, tsv, html, sarif, junit, template=<path>]. Default output is JSON")
+	rootCmd.PersistentFlags().StringVar(&outFile, "out-file", "", "Local file to write the report to instead of stdout. Placeholders: {date}, {report}, {ext} and {account} with --split-by-account. Ex: 'reports/{date}/{report}.{ext}'")
+	rootCmd.PersistentFlags().StringVar(&outS3, "out", "", "S3 URL to upload the report to instead of stdout, with the --out-file placeholders. Ex: 's3://bucket/prefix/{date}/{report}.{ext}'")
+	rootCmd.PersistentFlags().BoolVar(&splitByAccount, "split-by-account", false, "Write a report per account to --out-file and --out, which must contain the {account} placeholder")
+	rootCmd.PersistentFlags().StringSliceVar(&sortBy, "sort-by", []string{}, "One or more keys separated by a comma [,] to sort the rows of the table and mdtable outputs by. Options: [account, category, severity, region]. Default is the account, region and report specific order")
+	rootCmd.PersistentFlags().StringVar(&groupBy, "group-by", "", "Key to group the rows of the table and mdtable outputs by, with a table per value. Options: [account, category, severity, region]")
 	rootCmd.PersistentFlags().StringVarP(&region, "region", "r", "us-east-1", "AWS region to get results from")
+	rootCmd.PersistentFlags().StringVar(&regions, "regions", "", "One or more regions separated by a comma [,] or \"all\" for every region of the --region partition to run regional reports in. Defaults to --region")
+	rootCmd.PersistentFlags().BoolVar(&orgMode, "org", false, "Discover the accounts from AWS Organizations instead of --rolearn. Suspended accounts are skipped")
+	rootCmd.PersistentFlags().StringVar(&orgUnits, "org-units", "", "One or more organizational unit IDs separated by a comma [,] to limit --org accounts to. Nested organizational units are included")
+	rootCmd.PersistentFlags().StringVar(&orgTags, "org-tags", "", "Set of account tags in form [key:value] separated by [,] to limit --org accounts to")
+	rootCmd.PersistentFlags().StringVar(&assumeRoleName, "assume-role-name", "cloudig", "Name of the role to assume in each account discovered with --org")
+	rootCmd.PersistentFlags().StringVar(&failOn, "fail-on", "", "One or more conditions separated by a comma [,] that make the command exit with code 2 when met by the findings. Ex: 'new', 'ecrscan.CRITICAL>0', 'inspector.High>0', 'awsconfig.NON_COMPLIANT'")
+	rootCmd.PersistentFlags().IntVar(&maxConcurrency, "max-concurrency", 10, "Maximum number of accounts to process at the same time. Use 0 for no limit")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "Name of the AWS shared config profile to use. Defaults to the default credential chain")
+	rootCmd.PersistentFlags().StringVar(&externalID, "external-id", "", "External ID to assume the roles with")
+	rootCmd.PersistentFlags().StringVar(&externalIDFile, "external-id-file", "", "YAML file mapping account IDs to the external ID of their role. Overrides --external-id for the accounts it lists")
+	rootCmd.PersistentFlags().StringVar(&sessionName, "role-session-name", "cloudig", "Role session name identifying cloudig in the CloudTrail events of the accounts")
+	rootCmd.PersistentFlags().DurationVar(&duration, "duration", 15*time.Minute, "Duration of the role credentials, and of the MFA session credentials with --mfa-serial")
+	rootCmd.PersistentFlags().StringVar(&mfaSerial, "mfa-serial", "", "Serial number or ARN of the MFA device. The MFA code is prompted once to get session credentials the roles are assumed with")
+	rootCmd.PersistentFlags().StringVar(&viaRoles, "via-role", "", "One or more hub role ARNs separated by a comma [,] assumed in order before the role of each account. Accounts can have their own chain in the config file")
+	rootCmd.PersistentFlags().StringVar(&configFile, "config", "", "Config file defining named profile sets of flag values. Defaults to ~/.cloudig.yaml when it exists")
+	rootCmd.PersistentFlags().StringVar(&profileSetName, "profile-set", "", "Name of the profile set of the config file to use. Flags given on the command line override its values. Defaults to the 'default' profile set when the config file defines one")
 	rootCmd.PersistentFlags().IntVarP(&logger.Level, "verbose", "v", 3, "set log level, use 0 to silence, 1 for critical, 2 for warning, 3 for informational, 4 for debugging and 5 for debugging with AWS debug logging (default 3)")
 	// this is CLI , so turning of timestamp
 	logger.Timestamps = false
-	// healthCmd specific flags
-	healthCmd.PersistentFlags().BoolVarP(&details, "details", "d", false, "Flag to indicate level of printing for each notification (default false)")
-	healthCmd.PersistentFlags().StringVar(&pastDays, "pastdays", "", "Number of past days to get results from")
-	healthCmd.PersistentFlags().StringVar(&healthExcludeRegions, "exclude-regions", "", "Set of regions separated by [,] to exclude Health Notifications from. Takes precedence over include-regions")
-	healthCmd.PersistentFlags().StringVar(&healthIncludeRegions, "include-regions", "", "Set of regions separated by [,] to include Health Notifications from. Use \"global\" as a region if wanting notifications affecting all regions. Ignored when exclude-regions is set")
-
-	// ecrScanCmd specific flags
-	ecrScanCmd.PersistentFlags().StringVar(&ecrImageTag, "tag", "", "Tag of ECR image(s) to report scan results.")
-
-	// iamCmd specific flags
-	iamCmd.PersistentFlags().StringVarP(&identityARNs, "identity", "i", "", "One or more IAM Identities (users, groups, and roles) ARNs separated by a comma [,].Only role ARNs is supported today")
-	iamCmd.PersistentFlags().StringVarP(&identityTags, "identity-tags", "t", "", "Set of tags in form [key:value] separated by [,] to find the targeted IAM Identities. Only role ARN is supported today. Ignored when --identity is provided")
-	// if -u or -e is not provided both will be true, if one of them is provided then the other one is false
-	iamCmd.PersistentFlags().BoolVarP(&includeUsage, "usage", "u", false, "Reflect Identity usage data (default true, if --errors/-e is not explicitly provided)")
-	iamCmd.PersistentFlags().BoolVarP(&includeErrors, "errors", "e", false, "Reflect Identity error data (default true, if --usage/-u is not explicitly provided)")
-	iamCmd.PersistentFlags().BoolVar(&includeCallerIdentity, "caller-identity", false, "Include caller identity with the report(default false)")
-	iamCmd.PersistentFlags().StringVar(&absoluteTime, "absolute-time", "", "Specify both the start and end times for the time filter in the form 'startTime-endTime' 'mm/dd/yyyy-mm/dd/yyy' ex: '10/25/2020-10/31/2020'")
-	iamCmd.PersistentFlags().IntVar(&relativeTime, "relative-time", 1, "Specify a time filter relative to the current time in days. Default 1 day. Ignored when absolute-time is provided")
+	// log to stderr so that stdout is only the report
+	color.Output = colorable.NewColorableStderr()
+	// allCmd specific flags
+	allCmd.PersistentFlags().StringVar(&reportNames, "reports", "", "One or more reports separated by a comma [,] to get. Options: ["+strings.Join(getReportNames(""), ", ")+"] or their aliases. Default is all of them")
 }
 
 func execute(report cloudig.Report) {
-	sess, err := awslocal.NewAuthenticatedSession(region)
+	assumeRoleOptions, err := getAssumeRoleOptions()
 	if err != nil {
-		logger.Critical("error creating aws session: %v", err)
-		os.Exit(1)
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
 	}
 
 	// example type should be "*cloudig.HealthReport", we are spliting the string to get "HealthReport"
 	rType := strings.Split(fmt.Sprintf("%T", report), ".")[1]
-	logger.Debug("all root level flags:\ncommentsFile: %s\nroleARN: %s\noutput: %s\nregion: %s\nlogLevel: %d\n", commentsFile, roleARN, output, region, logger.Level)
-
-	if rType == "HealthReport" {
-		logger.Debug("all health command flags:\ndetails: %t\npastDays: %s\n", details, pastDays)
+	logger.Debug("all root level flags:\ncommentsFiles: %v\nroleARN: %s\noutput: %s\noutFile: %s\nout: %s\nsplitByAccount: %t\nsortBy: %v\ngroupBy: %s\nregion: %s\nregions: %s\nmaxConcurrency: %d\nlogLevel: %d\n", commentsFiles, roleARN, output, outFile, outS3, splitByAccount, sortBy, groupBy, region, regions, maxConcurrency, logger.Level)
+	logger.Debug("all credential flags:\nprofile: %s\nexternalIDFile: %s\nroleSessionName: %s\nduration: %s\nmfaSerial: %s\nviaRoles: %s\n", profile, externalIDFile, sessionName, duration, mfaSerial, viaRoles)
+	regionList, err := cloudig.ResolveRegions(regions, region)
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
+	}
+	failOnConditions, err := cloudig.ParseFailOn(report, failOn)
+	if err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
 	}
-	if rType == "ReflectReport" {
-		logger.Debug("all reflect command flags:\nidentityARNs: %s\nidentityTags: %s\nincludeUsage: %t\nincludeErrors: %t\nincludeCallerIdentity: %t\nabsoluteTime: %s\nrelativeTime: %d\n", identityARNs, identityTags, includeUsage, includeErrors, includeCallerIdentity, absoluteTime, relativeTime)
+	outputOptions := cloudig.OutputOptions{Type: output, File: outFile, S3: outS3, SplitByAccount: splitByAccount, SortBy: sortBy, GroupBy: groupBy}
+	// the output template is parsed here, before any AWS call
+	if err := cloudig.CheckOutputOptions(report, outputOptions); err != nil {
+		logger.Critical("%v", err)
+		os.Exit(exitCodeError)
 	}
 
-	err = cloudig.ProcessReport(sess, report, output, commentsFile, roleARN)
+	sess := newSession()
+	if orgMode {
+		err = cloudig.ProcessReportForAccounts(sess, report, outputOptions, commentsFiles, getOrganizationAccounts(sess), regionList, maxConcurrency, assumeRoleOptions)
+	} else {
+		err = cloudig.ProcessReport(sess, report, outputOptions, commentsFiles, roleARN, regionList, maxConcurrency, assumeRoleOptions)
+	}
 	if err != nil {
 		logger.Critical("error creating '%s': %v", rType, err)
+		os.Exit(exitCodeError)
+	}
+	if met := cloudig.CheckFailOn(report, failOnConditions); len(met) > 0 {
+		logger.Critical("fail-on conditions met: %s", strings.Join(met, ", "))
+		os.Exit(exitCodeFailOn)
+	}
+}
+
+// newSession returns the session of the credential flags
+func newSession() *session.Session {
+	sess, err := awslocal.NewAuthenticatedSessionWithOptions(region, awslocal.SessionOptions{Profile: profile, MFASerial: mfaSerial, Duration: duration})
+	if err != nil {
+		logger.Critical("error creating aws session: %v", err)
+		os.Exit(exitCodeError)
+	}
+	return sess
+}
+
+// getOrganizationAccounts returns the role ARNs of the accounts discovered with --org
+func getOrganizationAccounts(sess *session.Session) []string {
+	if roleARN != "" {
+		logger.Critical("--org and --rolearn can't be used together")
+		os.Exit(exitCodeError)
+	}
+	logger.Debug("all organization flags:\norgUnits: %s\norgTags: %s\nassumeRoleName: %s\n", orgUnits, orgTags, assumeRoleName)
+	var units []string
+	if orgUnits != "" {
+		units = strings.Split(orgUnits, ",")
+	}
+	accounts, err := cloudig.GetOrganizationRoleARNs(sess, assumeRoleName, units, cloudig.ParseTags(orgTags))
+	if err != nil {
+		logger.Critical("error discovering the accounts from the organization: %v", err)
+		os.Exit(exitCodeError)
+	}
+	return accounts
+}
+
+// newReportCmd returns the subcommand of a report type
+func newReportCmd(reportType cloudig.ReportType) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     reportType.Use,
+		Short:   reportType.Short,
+		Aliases: reportType.Aliases,
+
+		Run: func(cmd *cobra.Command, args []string) {
+			report, err := reportType.New(getReportOptions(reportType, nil))
+			if err != nil {
+				logger.Critical("%v", err)
+				os.Exit(exitCodeError)
+			}
+			execute(report)
+		},
+	}
+	addReportOptionFlags(cmd.PersistentFlags(), reportType)
+	return cmd
+}
+
+// addReportOptionFlags adds a flag of the type of its default for each option of the report type
+func addReportOptionFlags(flags *pflag.FlagSet, reportType cloudig.ReportType) {
+	for _, option := range reportType.Options {
+		switch value := option.Default.(type) {
+		case bool:
+			flags.BoolP(option.Name, option.Shorthand, value, option.Usage)
+		case int:
+			flags.IntP(option.Name, option.Shorthand, value, option.Usage)
+		default:
+			flags.StringP(option.Name, option.Shorthand, fmt.Sprint(value), option.Usage)
+		}
+	}
+}
+
+// addAllOptionFlags adds a flag without shorthand for each option of the report type that get all doesn't have yet.
+// Reports with an option of the same name share its flag
+func addAllOptionFlags(flags *pflag.FlagSet, reportType cloudig.ReportType) {
+	for _, option := range reportType.Options {
+		if flags.Lookup(option.Name) != nil {
+			continue
+		}
+		option.Shorthand = ""
+		option.Usage = "[" + reportType.Name + "] " + option.Usage
+		addReportOptionFlags(flags, cloudig.ReportType{Options: []cloudig.ReportOption{option}})
+	}
+}
+
+// getReportOptionValues returns the values of the option flags of the report type by option name. The flags of
+// overrides given on the command line or set by the profile set, ex: those of get all, override flags
+func getReportOptionValues(flags *pflag.FlagSet, overrides *pflag.FlagSet, reportType cloudig.ReportType) map[string]interface{} {
+	values := make(map[string]interface{})
+	for _, option := range reportType.Options {
+		optionFlags := flags
+		if overrides != nil {
+			if flag := overrides.Lookup(option.Name); flag != nil && (flag.Changed || flag.Value.String() != flag.DefValue) {
+				optionFlags = overrides
+			}
+		}
+		var value interface{}
+		var err error
+		switch option.Default.(type) {
+		case bool:
+			value, err = optionFlags.GetBool(option.Name)
+		case int:
+			value, err = optionFlags.GetInt(option.Name)
+		default:
+			value, err = optionFlags.GetString(option.Name)
+		}
+		if err != nil {
+			logger.Critical("%v", err)
+			os.Exit(exitCodeError)
+		}
+		values[option.Name] = value
+	}
+	return values
+}
+
+// getAssumeRoleOptions returns the options the roles of the accounts are assumed with
+func getAssumeRoleOptions() (awslocal.AssumeRoleOptions, error) {
+	options := awslocal.AssumeRoleOptions{ExternalID: externalID, RoleSessionName: sessionName, Duration: duration}
+	if viaRoles != "" {
+		options.ViaRoles = strings.Split(viaRoles, ",")
+	}
+	if externalIDFile != "" {
+		var err error
+		options.ExternalIDs, err = cloudig.ParseExternalIDsFile(externalIDFile)
+		if err != nil {
+			return options, err
+		}
+	}
+	// the external ID file takes precedence over the accounts of the config file
+	return config.GetAssumeRoleOptions(options), nil
+}
+
+// getReportOptions returns the global options and the values of the option flags of the report subcommand the report
+// type is configured with, overridden by the flags of overrides when not nil
+func getReportOptions(reportType cloudig.ReportType, overrides *pflag.FlagSet) cloudig.ReportOptions {
+	options := cloudig.ReportOptions{Region: region, Values: getReportOptionValues(reportCmds[reportType.Name].PersistentFlags(), overrides, reportType)}
+	if regions != "" && regions != "all" {
+		var err error
+		options.Regions, err = cloudig.ResolveRegions(regions, region)
+		if err != nil {
+			logger.Critical("%v", err)
+			os.Exit(exitCodeError)
+		}
+	}
+	return options
+}
+
+// getReportNames converts string "ta,config" to []string{"ta","config"}, every get report when the string is empty
+func getReportNames(names string) []string {
+	reports := make([]string, 0)
+	if names == "" {
+		for _, reportType := range cloudig.GetReportTypes(cloudig.CommandGet) {
+			reports = append(reports, reportType.Name)
+		}
+		return reports
+	}
+	for _, name := range strings.Split(names, ",") {
+		reports = append(reports, strings.TrimSpace(name))
 	}
+	return reports
 }
@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/Optum/cloudig/pkg/cloudig"
+
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetReportOptionValues(t *testing.T) {
+	reportType, _ := cloudig.GetReportType(cloudig.CommandGet, "health")
+	flags := pflag.NewFlagSet("health", pflag.ContinueOnError)
+	addReportOptionFlags(flags, reportType)
+	assert.NoError(t, flags.Parse([]string{"-d", "--pastdays", "7"}))
+	allFlags := pflag.NewFlagSet("all", pflag.ContinueOnError)
+	addAllOptionFlags(allFlags, reportType)
+	// get all has no shorthand for the options
+	assert.Error(t, allFlags.Parse([]string{"-d"}))
+
+	assert.Equal(t, map[string]interface{}{"details": true, "pastdays": "7", "exclude-regions": "", "include-regions": ""}, getReportOptionValues(flags, nil, reportType))
+
+	// the flags given to get all override those of the report subcommand
+	allFlags = pflag.NewFlagSet("all", pflag.ContinueOnError)
+	addAllOptionFlags(allFlags, reportType)
+	assert.NoError(t, allFlags.Parse([]string{"--pastdays", "30", "--exclude-regions", "us-west-2"}))
+	assert.Equal(t, map[string]interface{}{"details": true, "pastdays": "30", "exclude-regions": "us-west-2", "include-regions": ""}, getReportOptionValues(flags, allFlags, reportType))
+}
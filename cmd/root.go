@@ -44,6 +44,11 @@ Supported sources are:
 
 	// Version is set at compile time in parallel to rootCmd, so we need to read version after
 	Version: *(&version),
+
+	// fill in the flags that are not given on the command line from the config file
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applyProfileSet(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -52,6 +57,6 @@ func Execute() {
 	rootCmd.SetVersionTemplate("Beta release: {{ .Version }}\n")
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		os.Exit(exitCodeError)
 	}
 }
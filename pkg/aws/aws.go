@@ -19,6 +19,8 @@ import (
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/aws/aws-sdk-go/service/iam/iamiface"
 	"github.com/aws/aws-sdk-go/service/inspector"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/organizations/organizationsiface"
 	"github.com/aws/aws-sdk-go/service/sts"
 	"github.com/aws/aws-sdk-go/service/support"
 	"github.com/kris-nova/logger"
@@ -43,6 +45,7 @@ type APIs interface {
 	ECRSVC
 	CloudTrailSVC
 	AthenaSVC
+	OrganizationsSVC
 }
 
 // Client is the client for AWS API operations
@@ -57,29 +60,32 @@ type Client struct {
 	ECR            ecriface.ECRAPI
 	CloudTrail     cloudtrailiface.CloudTrailAPI
 	Athena         athenaiface.AthenaAPI
+	Organizations  organizationsiface.OrganizationsAPI
 }
 
 // NewClient creates a Client object that implement all the methods in the APIs interface
 func NewClient(sess *session.Session) APIs {
-	config := constructAWSConfig()
-	return &Client{
-		EC2:            ec2.New(sess, config),
-		TrustedAdvisor: support.New(sess, config),
-		AWSConfig:      configservice.New(sess, config),
-		Inspector:      inspector.New(sess, config),
-		STS:            sts.New(sess, config),
-		IAM:            iam.New(sess, config),
-		Health:         health.New(sess, config),
-		ECR:            ecr.New(sess, config),
-		CloudTrail:     cloudtrail.New(sess, config),
-		Athena:         athena.New(sess, config),
-	}
+	return newClientFromConfig(sess, constructAWSConfig())
 }
 
 // NewClientAsAssumeRole creates a Client object that assumes a role
-func NewClientAsAssumeRole(sess *session.Session, roleARN string) APIs {
-	creds := getRoleCredentials(sess, roleARN)
-	config := constructAWSConfig().WithCredentials(creds)
+func NewClientAsAssumeRole(sess *session.Session, roleARN string, options AssumeRoleOptions) APIs {
+	return NewClientWithCredentials(sess, NewRoleCredentials(sess, roleARN, options))
+}
+
+// NewClientWithCredentials creates a Client object that uses the given credentials
+func NewClientWithCredentials(sess *session.Session, creds *credentials.Credentials) APIs {
+	return newClientFromConfig(sess, constructAWSConfig().WithCredentials(creds))
+}
+
+// NewRoleCredentials returns credentials that assume a role, through the hub roles of the options if any. Credentials
+// are cached until they expire, so they can be shared by the clients of the same account in different regions
+func NewRoleCredentials(sess *session.Session, roleARN string, options AssumeRoleOptions) *credentials.Credentials {
+	return NewRoleCredentialsCache(sess, options).Get(roleARN)
+}
+
+func newClientFromConfig(sess *session.Session, config *aws.Config) APIs {
+	sess = withRateLimiter(sess, DefaultRateLimiter)
 	return &Client{
 		EC2:            ec2.New(sess, config),
 		TrustedAdvisor: support.New(sess, config),
@@ -91,21 +97,18 @@ func NewClientAsAssumeRole(sess *session.Session, roleARN string) APIs {
 		ECR:            ecr.New(sess, config),
 		CloudTrail:     cloudtrail.New(sess, config),
 		Athena:         athena.New(sess, config),
+		Organizations:  organizations.New(sess, config),
 	}
 }
 
 // NewAuthenticatedSession creates an AWS Session using the credentials from the running environment
 func NewAuthenticatedSession(region string) (*session.Session, error) {
-	sess, err := session.NewSession(aws.NewConfig().WithRegion(region))
-	if err != nil {
-		return nil, err
-	}
-	return sess, err
+	return NewAuthenticatedSessionWithOptions(region, SessionOptions{})
 }
 
 // Function that gets credentials for non-parent accounts
-func getRoleCredentials(sess *session.Session, roleARN string) (creds *credentials.Credentials) {
-	return stscreds.NewCredentials(sess, roleARN)
+func getRoleCredentials(sess *session.Session, roleARN string, options AssumeRoleOptions) (creds *credentials.Credentials) {
+	return stscreds.NewCredentials(sess, roleARN, options.apply(roleARN))
 }
 
 // constructAWSConfig is helper function to create and return pointer to aws config
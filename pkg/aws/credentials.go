@@ -0,0 +1,193 @@
+package aws
+
+import (
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/sts/stsiface"
+)
+
+// mfaSessionProviderName is the name of the provider of the MFA session credentials
+const mfaSessionProviderName = "MFASessionProvider"
+
+// credentialsExpiryWindow is how long before they expire the credentials are refreshed, so that a call made
+// during a long running query isn't signed with credentials about to expire
+const credentialsExpiryWindow = time.Minute
+
+// SessionOptions are the options of the credentials of the session
+type SessionOptions struct {
+	// Profile is the name of the shared config profile, the default credential chain is used when empty
+	Profile string
+	// MFASerial is the serial number or ARN of the MFA device. When set the session uses temporary credentials
+	// obtained with an MFA code, so that the code is prompted once rather than for every role assumed
+	MFASerial string
+	// Duration of the MFA session credentials, the STS default when zero
+	Duration time.Duration
+	// TokenProvider returns the MFA code, it is prompted on stdin when nil
+	TokenProvider func() (string, error)
+}
+
+// AssumeRoleOptions are the options used to assume the role of each account
+type AssumeRoleOptions struct {
+	// ExternalID is passed to every AssumeRole call, unless the account has one in ExternalIDs
+	ExternalID string
+	// ExternalIDs are the external IDs by account ID
+	ExternalIDs map[string]string
+	// RoleSessionName identifies the session in the CloudTrail events of the accounts, generated when empty
+	RoleSessionName string
+	// Duration of the role credentials, 15 minutes when zero. AWS limits chained roles to one hour
+	Duration time.Duration
+	// ViaRoles are the hub roles assumed in order before the role of each account, unless the account has a chain
+	// in AccountViaRoles
+	ViaRoles []string
+	// AccountViaRoles are the hub roles by account ID, an empty chain assumes the role of the account directly
+	AccountViaRoles map[string][]string
+}
+
+// RoleCredentialsCache creates the credentials of the roles of the accounts. The credentials of the hub roles are
+// shared by all the accounts assumed through them
+type RoleCredentialsCache struct {
+	sess    *session.Session
+	options AssumeRoleOptions
+	mu      sync.Mutex
+	cache   map[string]*credentials.Credentials
+}
+
+// NewRoleCredentialsCache returns a cache of role credentials assuming the roles with the session credentials
+func NewRoleCredentialsCache(sess *session.Session, options AssumeRoleOptions) *RoleCredentialsCache {
+	return &RoleCredentialsCache{
+		// AssumeRole calls are made with the rate limited session as well
+		sess:    withRateLimiter(sess, DefaultRateLimiter),
+		options: options,
+		cache:   make(map[string]*credentials.Credentials),
+	}
+}
+
+// Get returns the credentials of a role, assumed through its hub roles if any. Credentials are cached until they
+// expire and are refreshed along the chain when they do
+func (c *RoleCredentialsCache) Get(roleARN string) *credentials.Credentials {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	chain := append(append([]string{}, c.options.getViaRoles(roleARN)...), roleARN)
+	var creds *credentials.Credentials
+	for i, chainedARN := range chain {
+		key := strings.Join(chain[:i+1], ">")
+		cached, ok := c.cache[key]
+		if !ok {
+			sess := c.sess
+			if creds != nil {
+				sess = c.sess.Copy(aws.NewConfig().WithCredentials(creds))
+			}
+			cached = getRoleCredentials(sess, chainedARN, c.options)
+			c.cache[key] = cached
+		}
+		creds = cached
+	}
+	return creds
+}
+
+// NewAuthenticatedSessionWithOptions creates an AWS Session using the credentials of the profile, or the running
+// environment when no profile is given
+func NewAuthenticatedSessionWithOptions(region string, options SessionOptions) (*session.Session, error) {
+	tokenProvider := options.TokenProvider
+	if tokenProvider == nil {
+		tokenProvider = stscreds.StdinTokenProvider
+	}
+	sess, err := session.NewSessionWithOptions(session.Options{
+		Config:  *aws.NewConfig().WithRegion(region),
+		Profile: options.Profile,
+		// profiles assuming a role with mfa_serial prompt for the MFA code
+		AssumeRoleTokenProvider: tokenProvider,
+		SharedConfigState:       session.SharedConfigEnable,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if options.MFASerial != "" {
+		sess.Config.Credentials = credentials.NewCredentials(&mfaSessionProvider{
+			STS:           sts.New(sess),
+			serialNumber:  options.MFASerial,
+			duration:      options.Duration,
+			tokenProvider: tokenProvider,
+		})
+	}
+	return sess, nil
+}
+
+// getViaRoles returns the hub roles to assume before the role
+func (options AssumeRoleOptions) getViaRoles(roleARN string) []string {
+	if a, err := arn.Parse(roleARN); err == nil {
+		if viaRoles, ok := options.AccountViaRoles[a.AccountID]; ok {
+			return viaRoles
+		}
+	}
+	return options.ViaRoles
+}
+
+// getExternalID returns the external ID to assume a role with
+func (options AssumeRoleOptions) getExternalID(roleARN string) string {
+	if a, err := arn.Parse(roleARN); err == nil {
+		if externalID, ok := options.ExternalIDs[a.AccountID]; ok {
+			return externalID
+		}
+	}
+	return options.ExternalID
+}
+
+// apply sets the options on the provider of the role credentials
+func (options AssumeRoleOptions) apply(roleARN string) func(provider *stscreds.AssumeRoleProvider) {
+	return func(provider *stscreds.AssumeRoleProvider) {
+		if externalID := options.getExternalID(roleARN); externalID != "" {
+			provider.ExternalID = aws.String(externalID)
+		}
+		if options.RoleSessionName != "" {
+			provider.RoleSessionName = options.RoleSessionName
+		}
+		if options.Duration != 0 {
+			provider.Duration = options.Duration
+		}
+		provider.ExpiryWindow = credentialsExpiryWindow
+	}
+}
+
+// mfaSessionProvider retrieves temporary credentials with GetSessionToken and an MFA code
+type mfaSessionProvider struct {
+	credentials.Expiry
+	STS           stsiface.STSAPI
+	serialNumber  string
+	duration      time.Duration
+	tokenProvider func() (string, error)
+}
+
+// Retrieve prompts for the MFA code and gets the session credentials
+func (p *mfaSessionProvider) Retrieve() (credentials.Value, error) {
+	code, err := p.tokenProvider()
+	if err != nil {
+		return credentials.Value{ProviderName: mfaSessionProviderName}, err
+	}
+	input := &sts.GetSessionTokenInput{
+		SerialNumber: aws.String(p.serialNumber),
+		TokenCode:    aws.String(code),
+	}
+	if p.duration != 0 {
+		input.DurationSeconds = aws.Int64(int64(p.duration / time.Second))
+	}
+	result, err := p.STS.GetSessionToken(input)
+	if err != nil {
+		return credentials.Value{ProviderName: mfaSessionProviderName}, err
+	}
+	p.SetExpiration(aws.TimeValue(result.Credentials.Expiration), credentialsExpiryWindow)
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(result.Credentials.AccessKeyId),
+		SecretAccessKey: aws.StringValue(result.Credentials.SecretAccessKey),
+		SessionToken:    aws.StringValue(result.Credentials.SessionToken),
+		ProviderName:    mfaSessionProviderName,
+	}, nil
+}
@@ -0,0 +1,151 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Optum/cloudig/pkg/mocks"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+)
+
+func TestAssumeRoleOptionsApply(t *testing.T) {
+	testCases := []struct {
+		name             string
+		options          AssumeRoleOptions
+		roleARN          string
+		expectedProvider stscreds.AssumeRoleProvider
+	}{
+		{
+			name:             "Return provider without options",
+			options:          AssumeRoleOptions{},
+			roleARN:          "arn:aws:iam::111111111111:role/cloudig",
+			expectedProvider: stscreds.AssumeRoleProvider{RoleSessionName: "default", Duration: stscreds.DefaultDuration, ExpiryWindow: credentialsExpiryWindow},
+		},
+		{
+			name: "Return provider with options",
+			options: AssumeRoleOptions{
+				ExternalID:      "external-id",
+				ExternalIDs:     map[string]string{"222222222222": "external-id-2"},
+				RoleSessionName: "cloudig",
+				Duration:        time.Hour,
+			},
+			roleARN:          "arn:aws:iam::111111111111:role/cloudig",
+			expectedProvider: stscreds.AssumeRoleProvider{ExternalID: aws.String("external-id"), RoleSessionName: "cloudig", Duration: time.Hour, ExpiryWindow: credentialsExpiryWindow},
+		},
+		{
+			name: "Return provider with the external ID of the account",
+			options: AssumeRoleOptions{
+				ExternalID:  "external-id",
+				ExternalIDs: map[string]string{"222222222222": "external-id-2"},
+			},
+			roleARN:          "arn:aws:iam::222222222222:role/cloudig",
+			expectedProvider: stscreds.AssumeRoleProvider{ExternalID: aws.String("external-id-2"), RoleSessionName: "default", Duration: stscreds.DefaultDuration, ExpiryWindow: credentialsExpiryWindow},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			provider := stscreds.AssumeRoleProvider{RoleSessionName: "default", Duration: stscreds.DefaultDuration}
+			tc.options.apply(tc.roleARN)(&provider)
+			assert.Equal(t, tc.expectedProvider, provider)
+		})
+	}
+}
+
+func TestRoleCredentialsCacheGet(t *testing.T) {
+	sess := session.Must(session.NewSession(aws.NewConfig().WithRegion("us-east-1")))
+	cache := NewRoleCredentialsCache(sess, AssumeRoleOptions{
+		ViaRoles:        []string{"arn:aws:iam::999999999999:role/hub"},
+		AccountViaRoles: map[string][]string{"333333333333": {}},
+	})
+
+	creds := cache.Get("arn:aws:iam::111111111111:role/cloudig")
+	assert.Same(t, creds, cache.Get("arn:aws:iam::111111111111:role/cloudig"))
+	assert.False(t, creds == cache.Get("arn:aws:iam::222222222222:role/cloudig"))
+	cache.Get("arn:aws:iam::333333333333:role/cloudig")
+
+	// the hub role is assumed once for both accounts, and not for the account with an empty chain
+	keys := make([]string, 0)
+	for key := range cache.cache {
+		keys = append(keys, key)
+	}
+	assert.ElementsMatch(t, []string{
+		"arn:aws:iam::999999999999:role/hub",
+		"arn:aws:iam::999999999999:role/hub>arn:aws:iam::111111111111:role/cloudig",
+		"arn:aws:iam::999999999999:role/hub>arn:aws:iam::222222222222:role/cloudig",
+		"arn:aws:iam::333333333333:role/cloudig",
+	}, keys)
+}
+
+func TestMFASessionProviderRetrieve(t *testing.T) {
+	expiration := time.Now().Add(time.Hour)
+	testCases := []struct {
+		name           string
+		tokenError     error
+		apiResponse    *sts.GetSessionTokenOutput
+		apiError       error
+		expectedOutput credentials.Value
+		expectedError  error
+	}{
+		{
+			name: "Return session credentials",
+			apiResponse: &sts.GetSessionTokenOutput{
+				Credentials: &sts.Credentials{
+					AccessKeyId:     aws.String("AKID"),
+					SecretAccessKey: aws.String("SECRET"),
+					SessionToken:    aws.String("TOKEN"),
+					Expiration:      aws.Time(expiration),
+				},
+			},
+			expectedOutput: credentials.Value{AccessKeyID: "AKID", SecretAccessKey: "SECRET", SessionToken: "TOKEN", ProviderName: mfaSessionProviderName},
+		},
+		{
+			name:           "Return error from token provider",
+			tokenError:     errors.New("no token"),
+			expectedOutput: credentials.Value{ProviderName: mfaSessionProviderName},
+			expectedError:  errors.New("no token"),
+		},
+		{
+			name:           "Return error from API",
+			apiError:       errors.New("Some API error"),
+			expectedOutput: credentials.Value{ProviderName: mfaSessionProviderName},
+			expectedError:  errors.New("Some API error"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockSTSAPI := mocks.NewMockSTSAPI(mockCtrl)
+			if tc.tokenError == nil {
+				mockSTSAPI.EXPECT().GetSessionToken(&sts.GetSessionTokenInput{
+					SerialNumber:    aws.String("arn:aws:iam::111111111111:mfa/user"),
+					TokenCode:       aws.String("123456"),
+					DurationSeconds: aws.Int64(3600),
+				}).Return(tc.apiResponse, tc.apiError)
+			}
+			provider := &mfaSessionProvider{
+				STS:           mockSTSAPI,
+				serialNumber:  "arn:aws:iam::111111111111:mfa/user",
+				duration:      time.Hour,
+				tokenProvider: func() (string, error) { return "123456", tc.tokenError },
+			}
+
+			output, err := provider.Retrieve()
+			assert.Equal(t, tc.expectedOutput, output)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedError != nil, provider.IsExpired())
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package aws
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+)
+
+// OrganizationsSVC is a wrapper for Organizations API calls
+type OrganizationsSVC interface {
+	GetActiveAccounts(parentIDs []string, tags map[string]string) ([]string, error)
+}
+
+// GetActiveAccounts returns the IDs of all active accounts in the organization. When parentIDs are provided, only the accounts
+// under those roots or organizational units (including nested organizational units) are returned. When tags are provided,
+// only the accounts having all of the tags are returned
+func (client *Client) GetActiveAccounts(parentIDs []string, tags map[string]string) ([]string, error) {
+	var accounts []*organizations.Account
+	if len(parentIDs) == 0 {
+		allAccounts, err := client.listAccounts()
+		if err != nil {
+			return nil, err
+		}
+		accounts = allAccounts
+	}
+	for _, parentID := range parentIDs {
+		parentAccounts, err := client.listAccountsForParentRecursively(aws.String(parentID))
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, parentAccounts...)
+	}
+
+	accountIDs := make([]string, 0)
+	for _, account := range accounts {
+		if aws.StringValue(account.Status) != organizations.AccountStatusActive || Contains(accountIDs, aws.StringValue(account.Id)) {
+			continue
+		}
+		if len(tags) > 0 {
+			accountTags, err := client.listAccountTags(account.Id)
+			if err != nil {
+				return nil, err
+			}
+			if !containsAllAccountTags(tags, accountTags) {
+				continue
+			}
+		}
+		accountIDs = append(accountIDs, aws.StringValue(account.Id))
+	}
+	return accountIDs, nil
+}
+
+func (client *Client) listAccounts() ([]*organizations.Account, error) {
+	var nextToken *string
+	accounts := make([]*organizations.Account, 0)
+	for {
+		output, err := client.Organizations.ListAccounts(&organizations.ListAccountsInput{
+			NextToken: nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, output.Accounts...)
+
+		nextToken = output.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+// listAccountsForParentRecursively returns the accounts directly under the parent and under all of its child organizational units
+func (client *Client) listAccountsForParentRecursively(parentID *string) ([]*organizations.Account, error) {
+	var accountsNextToken *string
+	var ousNextToken *string
+	accounts := make([]*organizations.Account, 0)
+	for {
+		output, err := client.Organizations.ListAccountsForParent(&organizations.ListAccountsForParentInput{
+			ParentId:  parentID,
+			NextToken: accountsNextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, output.Accounts...)
+
+		accountsNextToken = output.NextToken
+		if accountsNextToken == nil {
+			break
+		}
+	}
+
+	for {
+		output, err := client.Organizations.ListOrganizationalUnitsForParent(&organizations.ListOrganizationalUnitsForParentInput{
+			ParentId:  parentID,
+			NextToken: ousNextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, ou := range output.OrganizationalUnits {
+			ouAccounts, err := client.listAccountsForParentRecursively(ou.Id)
+			if err != nil {
+				return nil, err
+			}
+			accounts = append(accounts, ouAccounts...)
+		}
+
+		ousNextToken = output.NextToken
+		if ousNextToken == nil {
+			break
+		}
+	}
+	return accounts, nil
+}
+
+func (client *Client) listAccountTags(accountID *string) ([]*organizations.Tag, error) {
+	var nextToken *string
+	tags := make([]*organizations.Tag, 0)
+	for {
+		output, err := client.Organizations.ListTagsForResource(&organizations.ListTagsForResourceInput{
+			ResourceId: accountID,
+			NextToken:  nextToken,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, output.Tags...)
+
+		nextToken = output.NextToken
+		if nextToken == nil {
+			break
+		}
+	}
+	return tags, nil
+}
+
+// containsAllAccountTags find if given map of tags are present in slice of Organizations tag
+func containsAllAccountTags(checkTags map[string]string, accountTags []*organizations.Tag) bool {
+	for k, v := range checkTags {
+		found := false
+		for _, tag := range accountTags {
+			if aws.StringValue(tag.Key) == k && aws.StringValue(tag.Value) == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package aws
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Optum/cloudig/pkg/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_GetActiveAccounts(t *testing.T) {
+	testCases := []struct {
+		name                       string
+		parentIDs                  []string
+		tags                       map[string]string
+		listAccountsResponses      []*organizations.ListAccountsOutput
+		listAccountsError          error
+		listAccountsForParent      map[string]*organizations.ListAccountsForParentOutput
+		listOUsForParent           map[string]*organizations.ListOrganizationalUnitsForParentOutput
+		listTagsForResourceOutputs map[string]*organizations.ListTagsForResourceOutput
+		expectedOutput             []string
+		expectedError              error
+	}{
+		{
+			name: "Return all active accounts and skip suspended ones",
+			listAccountsResponses: []*organizations.ListAccountsOutput{
+				{
+					Accounts: []*organizations.Account{
+						{Id: aws.String("111111111111"), Status: aws.String("ACTIVE")},
+						{Id: aws.String("222222222222"), Status: aws.String("SUSPENDED")},
+					},
+					NextToken: aws.String("token"),
+				},
+				{
+					Accounts: []*organizations.Account{
+						{Id: aws.String("333333333333"), Status: aws.String("ACTIVE")},
+					},
+				},
+			},
+			expectedOutput: []string{"111111111111", "333333333333"},
+		},
+		{
+			name: "Return active accounts having all of the tags",
+			tags: map[string]string{"env": "prod"},
+			listAccountsResponses: []*organizations.ListAccountsOutput{
+				{
+					Accounts: []*organizations.Account{
+						{Id: aws.String("111111111111"), Status: aws.String("ACTIVE")},
+						{Id: aws.String("333333333333"), Status: aws.String("ACTIVE")},
+					},
+				},
+			},
+			listTagsForResourceOutputs: map[string]*organizations.ListTagsForResourceOutput{
+				"111111111111": {Tags: []*organizations.Tag{{Key: aws.String("env"), Value: aws.String("dev")}}},
+				"333333333333": {Tags: []*organizations.Tag{{Key: aws.String("env"), Value: aws.String("prod")}, {Key: aws.String("team"), Value: aws.String("dig")}}},
+			},
+			expectedOutput: []string{"333333333333"},
+		},
+		{
+			name:      "Return active accounts under the organizational unit and its children",
+			parentIDs: []string{"ou-1"},
+			listAccountsForParent: map[string]*organizations.ListAccountsForParentOutput{
+				"ou-1": {Accounts: []*organizations.Account{{Id: aws.String("111111111111"), Status: aws.String("ACTIVE")}}},
+				"ou-2": {Accounts: []*organizations.Account{{Id: aws.String("222222222222"), Status: aws.String("ACTIVE")}}},
+			},
+			listOUsForParent: map[string]*organizations.ListOrganizationalUnitsForParentOutput{
+				"ou-1": {OrganizationalUnits: []*organizations.OrganizationalUnit{{Id: aws.String("ou-2")}}},
+				"ou-2": {},
+			},
+			expectedOutput: []string{"111111111111", "222222222222"},
+		},
+		{
+			name:                  "Return error",
+			listAccountsResponses: []*organizations.ListAccountsOutput{{}},
+			listAccountsError:     errors.New("AccessDeniedException"),
+			expectedError:         errors.New("AccessDeniedException"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockOrganizationsAPI := mocks.NewMockOrganizationsAPI(mockCtrl)
+			var nextToken *string
+			for _, response := range tc.listAccountsResponses {
+				mockOrganizationsAPI.EXPECT().ListAccounts(&organizations.ListAccountsInput{NextToken: nextToken}).Return(response, tc.listAccountsError)
+				nextToken = response.NextToken
+			}
+			for parentID, response := range tc.listAccountsForParent {
+				mockOrganizationsAPI.EXPECT().ListAccountsForParent(&organizations.ListAccountsForParentInput{ParentId: aws.String(parentID)}).Return(response, nil)
+			}
+			for parentID, response := range tc.listOUsForParent {
+				mockOrganizationsAPI.EXPECT().ListOrganizationalUnitsForParent(&organizations.ListOrganizationalUnitsForParentInput{ParentId: aws.String(parentID)}).Return(response, nil)
+			}
+			for accountID, response := range tc.listTagsForResourceOutputs {
+				mockOrganizationsAPI.EXPECT().ListTagsForResource(&organizations.ListTagsForResourceInput{ResourceId: aws.String(accountID)}).Return(response, nil)
+			}
+			client := &Client{
+				Organizations: mockOrganizationsAPI,
+			}
+
+			output, err := client.GetActiveAccounts(tc.parentIDs, tc.tags)
+			assert.Equal(t, tc.expectedError, err)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
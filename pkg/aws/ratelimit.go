@@ -0,0 +1,93 @@
+package aws
+
+import (
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go/service/support"
+)
+
+const defaultServiceRate float64 = 10
+
+// DefaultRateLimiter is shared by every Client so that all accounts together stay under the per-service API rate limits
+var DefaultRateLimiter = NewRateLimiter(defaultServiceRate, map[string]float64{
+	sts.ServiceName:           5,
+	support.ServiceName:       5,
+	configservice.ServiceName: 5,
+})
+
+// RateLimiter is a token bucket rate limiter keyed by AWS service name
+type RateLimiter struct {
+	mu          sync.Mutex
+	defaultRate float64
+	rates       map[string]float64
+	buckets     map[string]*tokenBucket
+}
+
+type tokenBucket struct {
+	tokens float64
+	rate   float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a RateLimiter allowing 'rates' requests per second for each service, or 'defaultRate' for services not in the map
+func NewRateLimiter(defaultRate float64, rates map[string]float64) *RateLimiter {
+	return &RateLimiter{
+		defaultRate: defaultRate,
+		rates:       rates,
+		buckets:     make(map[string]*tokenBucket),
+	}
+}
+
+// Wait blocks until a request to the given service is allowed
+func (l *RateLimiter) Wait(service string) {
+	time.Sleep(l.reserve(service, time.Now()))
+}
+
+// reserve takes a token from the service bucket and returns how long the caller has to wait for it
+func (l *RateLimiter) reserve(service string, now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket, ok := l.buckets[service]
+	if !ok {
+		rate, ok := l.rates[service]
+		if !ok {
+			rate = l.defaultRate
+		}
+		// bucket starts full, burst size is one second worth of requests
+		bucket = &tokenBucket{tokens: rate, rate: rate, last: now}
+		l.buckets[service] = bucket
+	}
+	if bucket.rate <= 0 {
+		return 0
+	}
+
+	bucket.tokens += now.Sub(bucket.last).Seconds() * bucket.rate
+	if bucket.tokens > bucket.rate {
+		bucket.tokens = bucket.rate
+	}
+	bucket.last = now
+	// tokens can go negative, which queues the next callers behind this one
+	bucket.tokens--
+	if bucket.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-bucket.tokens / bucket.rate * float64(time.Second))
+}
+
+// withRateLimiter returns a copy of the session whose service clients wait on the limiter before sending each request
+func withRateLimiter(sess *session.Session, limiter *RateLimiter) *session.Session {
+	limitedSess := sess.Copy()
+	limitedSess.Handlers.Send.PushFrontNamed(request.NamedHandler{
+		Name: "cloudig.RateLimiter",
+		Fn: func(r *request.Request) {
+			limiter.Wait(r.ClientInfo.ServiceName)
+		},
+	})
+	return limitedSess
+}
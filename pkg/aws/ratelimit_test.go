@@ -0,0 +1,60 @@
+package aws
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_reserve(t *testing.T) {
+	now := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		service   string
+		calls     int
+		elapsed   time.Duration
+		wantWaits []time.Duration
+	}{
+		{
+			name:      "Return no wait within the burst",
+			service:   "support",
+			calls:     2,
+			wantWaits: []time.Duration{0, 0},
+		},
+		{
+			name:      "Return increasing wait once the bucket is empty",
+			service:   "support",
+			calls:     4,
+			wantWaits: []time.Duration{0, 0, 500 * time.Millisecond, time.Second},
+		},
+		{
+			name:      "Return no wait after the bucket refilled",
+			service:   "support",
+			calls:     3,
+			elapsed:   time.Second,
+			wantWaits: []time.Duration{0, 0, 0},
+		},
+		{
+			name:      "Use default rate for unknown services",
+			service:   "ec2",
+			calls:     4,
+			wantWaits: []time.Duration{0, 0, 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRateLimiter(10, map[string]float64{"support": 2})
+			waits := make([]time.Duration, 0)
+			for i := 0; i < tt.calls; i++ {
+				// when elapsed is set, the first two calls empty the bucket and the rest happen once it refilled
+				at := now
+				if i >= 2 {
+					at = now.Add(tt.elapsed)
+				}
+				waits = append(waits, limiter.reserve(tt.service, at))
+			}
+			assert.Equal(t, tt.wantWaits, waits)
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package aws
+
+import (
+	"bytes"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// GetS3Object returns the content of an S3 object using the session credentials. The object is read from the region
+// of its bucket, which can differ from the session region
+func GetS3Object(sess *session.Session, bucket string, key string) ([]byte, error) {
+	region, err := s3manager.GetBucketRegion(aws.BackgroundContext(), sess, bucket, aws.StringValue(sess.Config.Region))
+	if err != nil {
+		return nil, err
+	}
+	return getS3Object(s3.New(sess, aws.NewConfig().WithRegion(region)), bucket, key)
+}
+
+func getS3Object(svc s3iface.S3API, bucket string, key string) ([]byte, error) {
+	result, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer result.Body.Close()
+	return ioutil.ReadAll(result.Body)
+}
+
+// PutS3Object uploads content as an S3 object using the session credentials. The object is written to the region of
+// its bucket, which can differ from the session region
+func PutS3Object(sess *session.Session, bucket string, key string, content []byte, contentType string) error {
+	region, err := s3manager.GetBucketRegion(aws.BackgroundContext(), sess, bucket, aws.StringValue(sess.Config.Region))
+	if err != nil {
+		return err
+	}
+	return putS3Object(s3.New(sess, aws.NewConfig().WithRegion(region)), bucket, key, content, contentType)
+}
+
+func putS3Object(svc s3iface.S3API, bucket string, key string, content []byte, contentType string) error {
+	_, err := svc.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
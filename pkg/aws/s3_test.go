@@ -0,0 +1,116 @@
+package aws
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetS3Object(t *testing.T) {
+	testCases := []struct {
+		name           string
+		key            string
+		expectedOutput []byte
+		expectedError  string
+	}{
+		{
+			name:           "Return object content",
+			key:            "cloudig/comments.yaml",
+			expectedOutput: []byte("- accountid: \"111111111111\"\n"),
+		},
+		{
+			name:          "Return error for a missing object",
+			key:           "cloudig/missing.yaml",
+			expectedError: "NoSuchKey",
+		},
+	}
+
+	// S3 API answering path style requests for the objects of bucket "exceptions"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/exceptions/cloudig/comments.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>`))
+			return
+		}
+		w.Write([]byte("- accountid: \"111111111111\"\n"))
+	}))
+	defer server.Close()
+	sess := session.Must(session.NewSession(aws.NewConfig().
+		WithRegion("us-east-1").
+		WithEndpoint(server.URL).
+		WithS3ForcePathStyle(true).
+		WithCredentials(credentials.NewStaticCredentials("id", "secret", ""))))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := getS3Object(s3.New(sess), "exceptions", tc.key)
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestPutS3Object(t *testing.T) {
+	testCases := []struct {
+		name          string
+		bucket        string
+		expectedError string
+	}{
+		{
+			name:   "Upload object content",
+			bucket: "reports",
+		},
+		{
+			name:          "Return error for a missing bucket",
+			bucket:        "missing",
+			expectedError: "NoSuchBucket",
+		},
+	}
+
+	// S3 API accepting path style uploads to bucket "reports"
+	uploads := make(map[string]string)
+	contentTypes := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut || !strings.HasPrefix(r.URL.Path, "/reports/") {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchBucket</Code><Message>The specified bucket does not exist.</Message></Error>`))
+			return
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		uploads[r.URL.Path] = string(body)
+		contentTypes[r.URL.Path] = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+	sess := session.Must(session.NewSession(aws.NewConfig().
+		WithRegion("us-east-1").
+		WithEndpoint(server.URL).
+		WithS3ForcePathStyle(true).
+		WithCredentials(credentials.NewStaticCredentials("id", "secret", ""))))
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := putS3Object(s3.New(sess), tc.bucket, "cloudig/2021-11-09/ecrscan.json", []byte(`{"findings":[]}`), "application/json")
+			if tc.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tc.expectedError)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, `{"findings":[]}`, uploads["/reports/cloudig/2021-11-09/ecrscan.json"])
+			assert.Equal(t, "application/json", contentTypes["/reports/cloudig/2021-11-09/ecrscan.json"])
+		})
+	}
+}
@@ -1,6 +1,8 @@
 package cloudig
 
 import (
+	"regexp"
+	"sort"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -12,17 +14,24 @@ import (
 
 // ConfigReport is a struct that contains an array of aws config compliance findings
 type ConfigReport struct {
-	Findings []configFinding `json:"findings"`
+	Findings []ConfigFinding `json:"findings"`
+	region   string
 	jsonOutputHelper
 }
 
-type configFinding struct {
+// ConfigFinding is an AWS Config rule with the resources not compliant with it
+type ConfigFinding struct {
 	AccountID string `json:"accountId"`
+	Region    string `json:"region"`
 	RuleName  string `json:"ruleName"`
 	//Description      string
 	Status           string              `json:"status"`
 	FlaggedResources map[string][]string `json:"flaggedResources"`
-	Comments         string              `json:"comments"`
+	// SuppressedResources are the comments of the flagged resources suppressed in the comments file by resource
+	SuppressedResources map[string]string `json:"suppressedResources,omitempty"`
+	Comments            string            `json:"comments"`
+	Owner               string            `json:"owner,omitempty"`
+	Ticket              string            `json:"ticket,omitempty"`
 }
 
 type configComplianceResult struct {
@@ -31,10 +40,32 @@ type configComplianceResult struct {
 	resultOutput []*configservice.EvaluationResult
 }
 
+func init() {
+	RegisterReportType(ReportType{
+		Name:        "awsconfig",
+		Title:       "AWS Config",
+		Command:     CommandGet,
+		Aliases:     []string{"config", "ac", "a"},
+		Short:       "Get AWS Config report findings",
+		CommentsKey: findingTypeAWSConfig,
+		// rule name, ex: IAM_PASSWORD_POLICY
+		CommentsKeyFormat: regexp.MustCompile(`^[A-Za-z0-9_-]+$`),
+		Report:            &ConfigReport{},
+		FailOnKeys:        []string{configservice.ComplianceTypeNonCompliant},
+		CountFindings:     countConfigFindings,
+		Table: func(report Report, tableType string) string {
+			return report.(*ConfigReport).toTable(tableType)
+		},
+		New: func(options ReportOptions) (Report, error) {
+			return &ConfigReport{}, nil
+		},
+	})
+}
+
 // GetReport retrives the aws config compliance report for a given account,
 func (report *ConfigReport) GetReport(client awslocal.APIs, comments []Comments) error {
 	start := time.Now()
-	finding := configFinding{}
+	finding := ConfigFinding{}
 
 	// Get accountID from session
 	accountID, err := client.GetAccountID()
@@ -43,6 +74,7 @@ func (report *ConfigReport) GetReport(client awslocal.APIs, comments []Comments)
 	}
 	logger.Info("working on AWSConfigCompliance report for account: %s", accountID)
 	finding.AccountID = accountID
+	finding.Region = report.region
 
 	logger.Info("finding failing compliance config rules for account: %s", accountID)
 	results, err := client.GetNonComplaintConfigRules()
@@ -57,21 +89,46 @@ func (report *ConfigReport) GetReport(client awslocal.APIs, comments []Comments)
 	return nil
 }
 
-func processConfigResults(results map[string][]*configservice.EvaluationResult, finding configFinding, comments []Comments) []configFinding {
-	var findings []configFinding
+func (report *ConfigReport) newReport(region string) Report {
+	return &ConfigReport{region: region}
+}
+
+func (report *ConfigReport) mergeReport(r Report) {
+	report.Findings = append(report.Findings, r.(*ConfigReport).Findings...)
+}
+
+func (report *ConfigReport) isRegional() bool {
+	return true
+}
+
+func (report *ConfigReport) recordCommentKeys(keys *CommentKeys) {
+	for _, finding := range report.Findings {
+		keys.record(finding.AccountID, findingTypeAWSConfig, finding.RuleName, finding.Comments == commentNewFinding)
+	}
+}
+
+func processConfigResults(results map[string][]*configservice.EvaluationResult, finding ConfigFinding, comments []Comments) []ConfigFinding {
+	var findings []ConfigFinding
 	for name, result := range results {
 		finding.RuleName = name
 		finding.Status = configservice.ComplianceTypeNonCompliant // keeping this for backword compatibility
-		finding.Comments = getComments(comments, finding.AccountID, findingTypeAWSConfig, finding.RuleName)
+		comment := getComments(comments, finding.AccountID, findingTypeAWSConfig, finding.RuleName)
+		finding.Comments, finding.Owner, finding.Ticket = comment.String(), comment.Owner, comment.Ticket
 		flaggedResources := []string{}
 		for _, evaluationResult := range result {
 			if aws.StringValue(evaluationResult.ComplianceType) != configservice.ComplianceTypeCompliant {
 				flaggedResources = append(flaggedResources, aws.StringValue(evaluationResult.EvaluationResultIdentifier.EvaluationResultQualifier.ResourceId))
 			}
 		}
+		flaggedResources, finding.SuppressedResources = comment.suppressResources(flaggedResources)
+		if len(flaggedResources) == 0 && len(finding.SuppressedResources) != 0 && isNewFinding(finding.Comments) {
+			finding.Comments = commentAllResourcesSuppressed
+		}
 		finding.FlaggedResources = map[string][]string{aws.StringValue(result[0].EvaluationResultIdentifier.EvaluationResultQualifier.ResourceType): flaggedResources}
 		findings = append(findings, finding)
 	}
+	// the rules come from a map, sort them so that the output doesn't change from run to run
+	sort.Slice(findings, func(i, j int) bool { return findings[i].RuleName < findings[j].RuleName })
 
 	return findings
 }
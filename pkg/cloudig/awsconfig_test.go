@@ -19,7 +19,7 @@ func TestAWSConfigGetReport(t *testing.T) {
 		name                          string
 		accountID                     string
 		complianceForConfigRules      map[string][]*configservice.EvaluationResult
-		expectedFindings              []configFinding
+		expectedFindings              []ConfigFinding
 		expectedGetAccountIDError     error
 		ComplianceForConfigRulesError error
 		expectedError                 error
@@ -98,7 +98,7 @@ func TestAWSConfigGetReport(t *testing.T) {
 					},
 				},
 			},
-			expectedFindings: []configFinding{
+			expectedFindings: []ConfigFinding{
 				{
 					AccountID:        "111111111111",
 					RuleName:         "ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK",
@@ -140,7 +140,7 @@ func TestAWSConfigGetReport(t *testing.T) {
 			mockAPIs.EXPECT().GetAccountID().Return(tc.accountID, tc.expectedGetAccountIDError).MaxTimes(1)
 			mockAPIs.EXPECT().GetNonComplaintConfigRules().Return(tc.complianceForConfigRules, tc.ComplianceForConfigRulesError).MaxTimes(1)
 			// Use comments file for testing
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 			report := ConfigReport{}
 			err := report.GetReport(mockAPIs, comments)
 
@@ -157,8 +157,8 @@ func TestProcessConfigResults(t *testing.T) {
 	testCases := []struct {
 		name           string
 		results        map[string][]*configservice.EvaluationResult
-		finding        configFinding
-		expectedOutput []configFinding
+		finding        ConfigFinding
+		expectedOutput []ConfigFinding
 	}{
 		{
 			name: "Return correct results",
@@ -234,20 +234,20 @@ func TestProcessConfigResults(t *testing.T) {
 					},
 				},
 			},
-			finding: configFinding{AccountID: "111111111111"},
-			expectedOutput: []configFinding{
+			finding: ConfigFinding{AccountID: "111111111111"},
+			expectedOutput: []ConfigFinding{
 				{
 					AccountID:        "111111111111",
-					RuleName:         "S3_BUCKET_LOGGING_ENABLED",
+					RuleName:         "ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK",
 					Status:           "NON_COMPLIANT",
-					FlaggedResources: map[string][]string{"AWS::S3::Bucket": {"dig-log-bucket-nonprod-222222222222"}},
+					FlaggedResources: map[string][]string{"AWS::EC2::SecurityGroup": {"sg-00003"}},
 					Comments:         "NEW_FINDING",
 				},
 				{
 					AccountID:        "111111111111",
-					RuleName:         "ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK",
+					RuleName:         "S3_BUCKET_LOGGING_ENABLED",
 					Status:           "NON_COMPLIANT",
-					FlaggedResources: map[string][]string{"AWS::EC2::SecurityGroup": {"sg-00003"}},
+					FlaggedResources: map[string][]string{"AWS::S3::Bucket": {"dig-log-bucket-nonprod-222222222222"}},
 					Comments:         "NEW_FINDING",
 				},
 			},
@@ -257,9 +257,9 @@ func TestProcessConfigResults(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Use comments file for testing
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 			output := processConfigResults(tc.results, tc.finding, comments)
-			assert.ElementsMatch(t, tc.expectedOutput, output)
+			assert.Equal(t, tc.expectedOutput, output)
 		})
 	}
 }
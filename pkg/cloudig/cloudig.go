@@ -1,104 +1,478 @@
 package cloudig
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"gopkg.in/yaml.v2"
 
 	awslocal "github.com/Optum/cloudig/pkg/aws"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/kris-nova/logger"
 )
 
 // Comments is a Collection of user comments mapped to yaml structure
 type Comments struct {
-	AccountID               string              `yaml:"accountid"`
-	TAFindings              []map[string]string `yaml:"ta-findings"`
-	ConfigFindings          []map[string]string `yaml:"config-findings"`
-	InspectorReportFindings []map[string]string `yaml:"inspector-findings"`
-	HealthReportFindings    []map[string]string `yaml:"health-findings"`
-	ImageScanFindings       []map[string]string `yaml:"ecr-findings"`
-	ReflectIAMFindings      []map[string]string `yaml:"reflect-iam-findings"`
+	// AccountID is the account the comments apply to, or "*" for every account
+	AccountID string `yaml:"accountid"`
+	// AccountIDs are the accounts the comments apply to when accountid is given as a list
+	AccountIDs              []string             `yaml:"-"`
+	TAFindings              []map[string]Comment `yaml:"ta-findings"`
+	ConfigFindings          []map[string]Comment `yaml:"config-findings"`
+	InspectorReportFindings []map[string]Comment `yaml:"inspector-findings"`
+	HealthReportFindings    []map[string]Comment `yaml:"health-findings"`
+	ImageScanFindings       []map[string]Comment `yaml:"ecr-findings"`
+	ReflectIAMFindings      []map[string]Comment `yaml:"reflect-iam-findings"`
+	// Sections holds the comments of the report types registered with a comments key not listed above
+	Sections map[string][]map[string]Comment `yaml:"-"`
 }
 
+// Comments keys of the built-in report types
 const (
-	findingTypeTrustedAdvisor string = "ta"
-	findingTypeAWSConfig      string = "config"
-	findingTypeInspector      string = "inspector"
-	findingTypeAWSHealth      string = "health"
-	findingTypeReflectIAM     string = "reflectIAM"
-	findingTypeECRScan        string = "ecrscan"
+	findingTypeTrustedAdvisor string = "ta-findings"
+	findingTypeAWSConfig      string = "config-findings"
+	findingTypeInspector      string = "inspector-findings"
+	findingTypeAWSHealth      string = "health-findings"
+	findingTypeReflectIAM     string = "reflect-iam-findings"
+	findingTypeECRScan        string = "ecr-findings"
 )
 
+// UnmarshalYAML parses the comments of the built-in report types into their fields and the other sections into Sections
+func (c *Comments) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var sections map[string]interface{}
+	if err := unmarshal(&sections); err != nil {
+		return err
+	}
+	if accountIDs, ok := sections["accountid"].([]interface{}); ok {
+		// parse the comments without the list of accounts, then set it
+		delete(sections, "accountid")
+		content, err := yaml.Marshal(sections)
+		if err != nil {
+			return err
+		}
+		if err := yaml.Unmarshal(content, c); err != nil {
+			return err
+		}
+		for _, accountID := range accountIDs {
+			c.AccountIDs = append(c.AccountIDs, fmt.Sprint(accountID))
+		}
+		return nil
+	}
+	type plain Comments
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	for key := range sections {
+		switch key {
+		case "accountid", findingTypeTrustedAdvisor, findingTypeAWSConfig, findingTypeInspector, findingTypeAWSHealth, findingTypeReflectIAM, findingTypeECRScan:
+			continue
+		}
+		content, err := yaml.Marshal(sections[key])
+		if err != nil {
+			return err
+		}
+		var section []map[string]Comment
+		if err := yaml.Unmarshal(content, &section); err != nil {
+			return fmt.Errorf("unable to parse the comments section '%s': %v", key, err)
+		}
+		if c.Sections == nil {
+			c.Sections = make(map[string][]map[string]Comment)
+		}
+		c.Sections[key] = section
+	}
+	return nil
+}
+
+// getSection returns the comments of the section with the given key
+func (c Comments) getSection(key string) []map[string]Comment {
+	switch key {
+	case findingTypeTrustedAdvisor:
+		return c.TAFindings
+	case findingTypeAWSConfig:
+		return c.ConfigFindings
+	case findingTypeInspector:
+		return c.InspectorReportFindings
+	case findingTypeAWSHealth:
+		return c.HealthReportFindings
+	case findingTypeReflectIAM:
+		return c.ReflectIAMFindings
+	case findingTypeECRScan:
+		return c.ImageScanFindings
+	default:
+		return c.Sections[key]
+	}
+}
+
+// sectionKeys returns the keys of the sections of the comments, the sections of the built-in report types first
+func (c Comments) sectionKeys() []string {
+	keys := make([]string, 0)
+	for _, key := range []string{findingTypeTrustedAdvisor, findingTypeAWSConfig, findingTypeInspector, findingTypeAWSHealth, findingTypeReflectIAM, findingTypeECRScan} {
+		if c.getSection(key) != nil {
+			keys = append(keys, key)
+		}
+	}
+	sections := make([]string, 0, len(c.Sections))
+	for key := range c.Sections {
+		sections = append(sections, key)
+	}
+	sort.Strings(sections)
+	return append(keys, sections...)
+}
+
 // Report is an interface that all types of reports will implement
 type Report interface {
 	GetReport(client awslocal.APIs, comments []Comments) error
 	toJSON(report *Report) string
-	toTable(tableType string) string
+	// toRecords returns the header and the rows of the CSV output, one row per finding per flagged resource. The header
+	// doesn't depend on the findings
+	toRecords() [][]string
+	// toHTML returns the sections of the HTML output, one per report
+	toHTML() []htmlSection
+	// toTestCases returns a test case per finding for the JUnit output
+	toTestCases() []junitTestCase
+	// newReport returns an empty report of the same type and flags, used to collect the findings of a single account in a region
+	newReport(region string) Report
+	// mergeReport appends the findings collected by a single account report of the same type
+	mergeReport(r Report)
+	// isRegional tells whether the report has to be run in each region. Non-regional reports are run once per account
+	isRegional() bool
+	// recordCommentKeys records the comments file keys of the findings, used to lint the comments against a saved report
+	recordCommentKeys(keys *CommentKeys)
+	// setErrors sets the errors section of the JSON output
+	setErrors(reportErrors []ReportError)
+	// forAccount returns a copy of the report with the findings of an account only, used to split the output by account
+	forAccount(accountID string) Report
+	// accountIDs returns the IDs of the accounts with findings, in the order of the findings
+	accountIDs() []string
 }
 
-// ProcessReport collects the different reports for each account concurrently
-func ProcessReport(sess *session.Session, report Report, outputType string, commentsFile string, roleARNs string) error {
-	var wg sync.WaitGroup
+// Stages of a report for an account reported in the errors section of the output
+const (
+	stageAssumeRole string = "assumeRole"
+	stageGetReport  string = "getReport"
+)
+
+// accountResult is the outcome of running a report against a single account in a region
+type accountResult struct {
+	account string
+	region  string
+	report  Report
+	stage   string
+	err     error
+}
+
+// ReportError describes why a report couldn't be collected for an account, so that an account without findings
+// can be told apart from an account that couldn't be looked at
+type ReportError struct {
+	Account   string `json:"account"`
+	AccountID string `json:"accountId"`
+	Region    string `json:"region"`
+	Report    string `json:"report"`
+	Stage     string `json:"stage"`
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+}
+
+func (e ReportError) Error() string {
+	return fmt.Sprintf("%s in %s: %s", e.Account, e.Region, e.Message)
+}
 
-	// Parse comments file into map and pass to report
-	comments := parseCommentsFile(commentsFile)
+// ProcessReport collects the different reports for each account and region concurrently, running at most maxConcurrency at a time
+func ProcessReport(sess *session.Session, report Report, outputOptions OutputOptions, commentsFiles []string, roleARNs string, regions []string, maxConcurrency int, assumeRoleOptions awslocal.AssumeRoleOptions) error {
 	accounts := parseRoleARNs(roleARNs)
 	logger.Debug("accounts derived from role ARN is: %v", accounts)
-	parentClient := awslocal.NewClient(sess)
+	return ProcessReportForAccounts(sess, report, outputOptions, commentsFiles, accounts, regions, maxConcurrency, assumeRoleOptions)
+}
+
+// ProcessReportForAccounts collects the different reports for each of the given role ARNs and regions concurrently, running
+// at most maxConcurrency at a time, and outputs the report with outputOptions. Use "parent" as role ARN to collect the report using the session credentials.
+// Non-regional reports are collected once per account in the session region, or in the first region for a composite report
+func ProcessReportForAccounts(sess *session.Session, report Report, outputOptions OutputOptions, commentsFiles []string, accounts []string, regions []string, maxConcurrency int, assumeRoleOptions awslocal.AssumeRoleOptions) error {
+	// Parse comments files into map and pass to report
+	comments, err := LoadComments(sess, commentsFiles)
+	if err != nil {
+		return err
+	}
+	reportErrors := CollectReport(sess, report, comments, accounts, regions, maxConcurrency, assumeRoleOptions)
+	if outputOptions.SplitByAccount {
+		outputOptions.AccountIDs = getCollectedAccountIDs(awslocal.NewClient(sess), accounts)
+	}
+
+	// output even when every account failed, the errors section tells which accounts couldn't be looked at
+	es := make([]string, 0, len(reportErrors)+1)
+	if err := OutputReport(sess, report, outputOptions, reportErrors); err != nil {
+		es = append(es, err.Error())
+	}
+	for _, e := range reportErrors {
+		es = append(es, e.Error())
+	}
+	if len(es) != 0 {
+		return errors.New(strings.Join(es, "\n"))
+	}
+	return nil
+}
+
+// CollectReport collects the findings of each of the given role ARNs and regions into report concurrently, running at
+// most maxConcurrency at a time, and returns the errors of the accounts that couldn't be reported. Use "parent" as role ARN
+// to collect the report using the session credentials. Non-regional reports are collected once per account in the session
+// region, or in the first region for a composite report. The roles are assumed with assumeRoleOptions
+func CollectReport(sess *session.Session, report Report, comments []Comments, accounts []string, regions []string, maxConcurrency int, assumeRoleOptions awslocal.AssumeRoleOptions) []ReportError {
+	if len(regions) == 0 || !report.isRegional() {
+		regions = []string{aws.StringValue(sess.Config.Region)}
+	}
+	logger.Debug("regions to collect the report from: %v", regions)
+	if composite, ok := report.(*CompositeReport); ok {
+		composite.globalRegion = regions[0]
+	}
+
+	results := collectAccountResults(report, accounts, regions, comments, maxConcurrency, newClientFactory(sess, assumeRoleOptions))
+	return mergeAccountResults(report, results)
+}
+
+// newClientFactory returns a function creating the client for an account and region. Role credentials are shared
+// by all the regions of an account so the role is assumed once per account
+func newClientFactory(sess *session.Session, assumeRoleOptions awslocal.AssumeRoleOptions) func(account, region string) (awslocal.APIs, error) {
+	roleCredentials := awslocal.NewRoleCredentialsCache(sess, assumeRoleOptions)
+	return func(account, region string) (awslocal.APIs, error) {
+		regionalSess := sess.Copy(aws.NewConfig().WithRegion(region))
+		// if not the parent account, create a new Client that assumes the role tied to the other account
+		if account == "parent" {
+			return awslocal.NewClient(regionalSess), nil
+		}
+		creds := roleCredentials.Get(account)
+		// assume the role upfront to tell a role that can't be assumed apart from a failing report
+		if _, err := creds.Get(); err != nil {
+			return nil, err
+		}
+		return awslocal.NewClientWithCredentials(regionalSess, creds), nil
+	}
+}
+
+// collectAccountResults runs the report against every account and region with a pool of maxConcurrency workers. Each
+// account and region collects its findings into its own report so that no state is shared between the go routines.
+// Results are sorted by account ID and region
+func collectAccountResults(report Report, accounts []string, regions []string, comments []Comments, maxConcurrency int, newClient func(account, region string) (awslocal.APIs, error)) []accountResult {
+	var wg sync.WaitGroup
+	results := make([]accountResult, 0, len(accounts)*len(regions))
+	for _, account := range accounts {
+		for _, region := range regions {
+			results = append(results, accountResult{account: account, region: region})
+		}
+	}
+
+	// zero or negative means no limit
+	if maxConcurrency <= 0 || maxConcurrency > len(results) {
+		maxConcurrency = len(results)
+	}
+	jobs := make(chan int, len(results))
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
 
 	// Add all go routines to be executed to wait group for effective synchronization
-	wg.Add(len(accounts))
-	es := make([]string, 0)
-	for i := 0; i < len(accounts); i++ {
-		go func(i int) {
+	wg.Add(maxConcurrency)
+	for w := 0; w < maxConcurrency; w++ {
+		go func() {
 			defer wg.Done()
-
-			// if not the parent account, create a new Client that assumes the role tied to the other account
-			client := parentClient
-			if accounts[i] != "parent" {
-				client = awslocal.NewClientAsAssumeRole(sess, accounts[i])
+			for i := range jobs {
+				account, region := results[i].account, results[i].region
+				accountReport := report.newReport(region)
+				results[i].report = accountReport
+				client, err := newClient(account, region)
+				if err != nil {
+					logger.Warning("error assuming the role for the account '%s' in region '%s': %v", account, region, err)
+					results[i].stage, results[i].err = stageAssumeRole, err
+					continue
+				}
+				err = accountReport.GetReport(client, comments)
+				if err != nil {
+					logger.Warning("error getting the report for the account '%s' in region '%s': %v", account, region, err)
+					results[i].stage, results[i].err = stageGetReport, err
+				}
 			}
+		}()
+	}
+	// Wait till all called in go routines are completed successfully
+	wg.Wait()
 
-			err := report.GetReport(client, comments)
+	sort.SliceStable(results, func(i, j int) bool {
+		accountI, accountJ := accountIDFromRoleARN(results[i].account), accountIDFromRoleARN(results[j].account)
+		if accountI != accountJ {
+			return accountI < accountJ
+		}
+		return results[i].region < results[j].region
+	})
+	return results
+}
+
+// mergeAccountResults merges the findings of every successful account into report and returns the errors of the failed ones
+func mergeAccountResults(report Report, results []accountResult) []ReportError {
+	reportErrors := make([]ReportError, 0)
+	for _, result := range results {
+		if result.err == nil {
+			report.mergeReport(result.report)
+			continue
+		}
+		composite, isComposite := result.report.(*CompositeReport)
+		if !isComposite || result.stage != stageGetReport {
+			reportErrors = append(reportErrors, newReportError(result, getReportName(report), result.err))
+			continue
+		}
+		// a composite report still has the findings of the reports that didn't fail
+		for i, err := range composite.errs {
 			if err != nil {
-				logger.Warning("error getting the report for the account '%s': %v", accounts[i], err)
-				es = append(es, err.Error())
+				reportErrors = append(reportErrors, newReportError(result, getReportName(composite.Reports[i]), err))
 			}
+		}
+		report.mergeReport(result.report)
+	}
+	return reportErrors
+}
 
-		}(i)
+func newReportError(result accountResult, reportName string, err error) ReportError {
+	reportErr := ReportError{
+		Account:   result.account,
+		AccountID: accountIDFromRoleARN(result.account),
+		Region:    result.region,
+		Report:    reportName,
+		Stage:     result.stage,
+		Message:   err.Error(),
 	}
-	// Wait till all called in go routines are completed successfully
-	wg.Wait()
+	var awsErr awserr.Error
+	if errors.As(err, &awsErr) {
+		reportErr.Code = awsErr.Code()
+	}
+	return reportErr
+}
 
-	// output only if there is no error on at least one of the account
-	if len(es) != len(accounts) {
-		outputReport(report, outputType)
+// accountIDFromRoleARN returns the account ID of a role ARN, or the value itself when it is not a valid ARN (ex: "parent")
+func accountIDFromRoleARN(roleARN string) string {
+	a, err := arn.Parse(roleARN)
+	if err != nil {
+		return roleARN
 	}
+	return a.AccountID
+}
 
-	if len(es) != 0 {
-		return fmt.Errorf(strings.Join(es, "\n"))
+// GetCollectedAccountIDs returns the IDs of the accounts of the role ARNs given to CollectReport whose findings were
+// all collected, the accounts of reportErrors are left out. The ID of the "parent" account is resolved with the session
+// credentials
+func GetCollectedAccountIDs(sess *session.Session, accounts []string, reportErrors []ReportError) []string {
+	collected := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		failed := false
+		for _, e := range reportErrors {
+			failed = failed || e.AccountID == accountIDFromRoleARN(account)
+		}
+		if !failed {
+			collected = append(collected, account)
+		}
 	}
-	return nil
+	return getCollectedAccountIDs(awslocal.NewClient(sess), collected)
+}
+
+// getCollectedAccountIDs returns the IDs of the accounts of the role ARNs, resolving the ID of the "parent" account with
+// the session credentials. The parent account is left out when its ID can't be resolved
+func getCollectedAccountIDs(client awslocal.STSSVC, accounts []string) []string {
+	accountIDs := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		if account != "parent" {
+			accountIDs = append(accountIDs, accountIDFromRoleARN(account))
+			continue
+		}
+		accountID, err := client.GetAccountID()
+		if err != nil {
+			logger.Warning("unable to get the ID of the parent account: %v", err)
+			continue
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+	return accountIDs
 }
 
-// OutputReport outputs a report as JSON, an ASCII table, or a markdown table
-func outputReport(reportType Report, outputType string) {
+// RenderReport renders a report as JSON, an ASCII table, a markdown table, CSV, TSV, an HTML document, a SARIF log,
+// JUnit XML or through the text/template of a "template=<path>" output type. Errors are rendered as the errors section
+// in JSON, HTML and templates, as a footer table in tables and as test case errors in JUnit. They are left out of CSV,
+// TSV and SARIF. SARIF only has the runs of the reports supporting it, see CheckOutputType. An error is returned when
+// the template can't be parsed or rendered
+func RenderReport(report Report, outputType string, reportErrors []ReportError) (string, error) {
+	if strings.HasPrefix(outputType, OutputTypeTemplatePrefix) {
+		return toTemplate(report, outputType, reportErrors)
+	}
 	switch outputType {
-	case tableTypeNormal:
-		fmt.Println(reportType.toTable(tableTypeNormal))
-	case tableTypeMD:
-		fmt.Println(reportType.toTable(tableTypeMD))
+	case OutputTypeCSV:
+		return recordsToCSV(report.toRecords(), ','), nil
+	case OutputTypeTSV:
+		return recordsToCSV(report.toRecords(), '\t'), nil
+	case OutputTypeHTML:
+		return toHTMLDocument(report, reportErrors), nil
+	case OutputTypeSARIF:
+		return toSARIFLog(report), nil
+	case OutputTypeJUnit:
+		return toJUnitXML(report, reportErrors), nil
+	case OutputTypeTable:
+		return renderTable(report, tableTypeNormal) + errorsToTable(tableTypeNormal, reportErrors), nil
+	case OutputTypeMDTable:
+		return renderTable(report, tableTypeMD) + errorsToTable(tableTypeMD, reportErrors), nil
 	default:
-		fmt.Println(reportType.toJSON(&reportType))
+		report.setErrors(reportErrors)
+		return report.toJSON(&report), nil
+	}
+}
+
+// ParseReportJSON parses a report rendered as JSON by RenderReport. The report of a composite JSON is found from its
+// sections, reportName is the name or alias of the report of other JSON
+func ParseReportJSON(content []byte, reportName string) (Report, error) {
+	var sections map[string]json.RawMessage
+	if err := json.Unmarshal(content, &sections); err != nil {
+		return nil, fmt.Errorf("unable to parse report: %v", err)
+	}
+	composite := &CompositeReport{}
+	for _, reportType := range GetReportTypes("") {
+		section, ok := sections[reportType.Name]
+		if !ok {
+			continue
+		}
+		report, err := parseReportTypeJSON(section, reportType)
+		if err != nil {
+			return nil, err
+		}
+		composite.Reports = append(composite.Reports, report)
+	}
+	if len(composite.Reports) != 0 {
+		return composite, nil
+	}
+	reportType, ok := GetReportType("", reportName)
+	if !ok {
+		return nil, fmt.Errorf("unknown report '%s' of the JSON report", reportName)
 	}
+	return parseReportTypeJSON(content, reportType)
 }
 
-// Function that parses comments file into map
-func parseCommentsFile(commentsFile string) []Comments {
+// parseReportTypeJSON parses a report of the report type rendered as JSON
+func parseReportTypeJSON(content []byte, reportType ReportType) (Report, error) {
+	report := reflect.New(reflect.TypeOf(reportType.Report).Elem()).Interface().(Report)
+	if err := json.Unmarshal(content, report); err != nil {
+		return nil, fmt.Errorf("unable to parse %s report: %v", reportType.Name, err)
+	}
+	return report, nil
+}
+
+// ParseCommentsFile parses the comments file, no comments are returned when the file can't be read or parsed
+func ParseCommentsFile(commentsFile string) []Comments {
 	var comments []Comments
 
 	content, err := ioutil.ReadFile(commentsFile)
@@ -116,37 +490,125 @@ func parseCommentsFile(commentsFile string) []Comments {
 	return comments
 }
 
-func getComments(comments []Comments, findingAcct string, findingType string, findingName string) string {
-	for _, ex := range comments {
-		if ex.AccountID == findingAcct {
-			switch findingType {
-			case findingTypeTrustedAdvisor:
-				return ContainsKey(ex.TAFindings, findingName)
-			case findingTypeAWSConfig:
-				return ContainsKey(ex.ConfigFindings, findingName)
-			case findingTypeInspector:
-				return ContainsKey(ex.InspectorReportFindings, findingName)
-			case findingTypeAWSHealth:
-				return ContainsKey(ex.HealthReportFindings, findingName)
-			case findingTypeReflectIAM:
-				return ContainsKey(ex.ReflectIAMFindings, findingName)
-			case findingTypeECRScan:
-				var allTag string
-				tag := strings.Split(findingName, ":")
-				if len(tag) >= 2 {
-					allTag = "ALL:" + tag[1]
-				}
-				value := ContainsKey(ex.ImageScanFindings, findingName)
-				if value == "NEW_FINDING" {
-					return ContainsKey(ex.ImageScanFindings, allTag)
+// commentsHTTPClient downloads the comments files given as https:// URLs
+var commentsHTTPClient = &http.Client{Timeout: time.Minute}
+
+// LoadComments parses the comments files and merges them. A comments file is a local path, an s3://bucket/key URL read
+// with the session credentials or an https:// URL. The comments of later files override the ones of earlier files for
+// the same account and key. Local files are parsed with ParseCommentsFile, an error is returned when a URL can't be
+// read or parsed
+func LoadComments(sess *session.Session, commentsFiles []string) ([]Comments, error) {
+	files := make([][]Comments, 0, len(commentsFiles))
+	for _, commentsFile := range commentsFiles {
+		if !IsCommentsURL(commentsFile) {
+			files = append(files, ParseCommentsFile(commentsFile))
+			continue
+		}
+		content, err := ReadCommentsFile(sess, commentsFile)
+		if err != nil {
+			return nil, err
+		}
+		logger.Info("reading comments from %s", commentsFile)
+		var comments []Comments
+		err = yaml.Unmarshal(content, &comments)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse comments from %s: %v", commentsFile, err)
+		}
+		files = append(files, comments)
+	}
+	return mergeComments(commentsFiles, files), nil
+}
+
+// IsCommentsURL tells whether a comments file is given as a URL rather than a local path
+func IsCommentsURL(commentsFile string) bool {
+	return strings.Contains(commentsFile, "://")
+}
+
+// ReadCommentsFile returns the content of a comments file given as a local path, an s3://bucket/key URL or an
+// https:// URL
+func ReadCommentsFile(sess *session.Session, commentsFile string) ([]byte, error) {
+	if !IsCommentsURL(commentsFile) {
+		content, err := ioutil.ReadFile(commentsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading file %s: %v", commentsFile, err)
+		}
+		return content, nil
+	}
+	u, err := url.Parse(commentsFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid comments URL %s: %v", commentsFile, err)
+	}
+	switch u.Scheme {
+	case "s3":
+		content, err := awslocal.GetS3Object(sess, u.Host, strings.TrimPrefix(u.Path, "/"))
+		if err != nil {
+			return nil, fmt.Errorf("error reading comments from %s: %v", commentsFile, err)
+		}
+		return content, nil
+	case "https":
+		resp, err := commentsHTTPClient.Get(commentsFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading comments from %s: %v", commentsFile, err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("error reading comments from %s: %s", commentsFile, resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	default:
+		return nil, fmt.Errorf("unsupported comments URL %s, use a local path, s3:// or https://", commentsFile)
+	}
+}
+
+// mergeComments merges the comments of the files. The comments of later files come first so that they win over the
+// comments of earlier files for the same account and key, the first comment winning among comments of the same
+// precedence. The file each comment comes from is logged for debugging
+func mergeComments(sources []string, files [][]Comments) []Comments {
+	merged := make([]Comments, 0)
+	for i := len(files) - 1; i >= 0; i-- {
+		merged = append(merged, files[i]...)
+	}
+
+	provenance := make(map[string]string)
+	for i, comments := range files {
+		for _, entry := range comments {
+			for _, section := range entry.sectionKeys() {
+				for _, item := range entry.getSection(section) {
+					keys := make([]string, 0, len(item))
+					for key := range item {
+						keys = append(keys, key)
+					}
+					sort.Strings(keys)
+					for _, key := range keys {
+						for _, accountID := range entry.accountIDs() {
+							id := accountID + "/" + section + "/" + key
+							if previous, ok := provenance[id]; ok {
+								logger.Debug("comment of account %s in %s for '%s' from %s overrides %s", accountID, section, key, sources[i], previous)
+							} else {
+								logger.Debug("comment of account %s in %s for '%s' from %s", accountID, section, key, sources[i])
+							}
+							provenance[id] = sources[i]
+						}
+					}
 				}
-				return value
-			default:
-				return "NEW_FINDING"
 			}
 		}
 	}
-	return "NEW_FINDING"
+	return merged
+}
+
+// ParseExternalIDsFile parses a YAML file mapping account IDs to the external ID of their role, ex: "111111111111: id"
+func ParseExternalIDsFile(externalIDsFile string) (map[string]string, error) {
+	externalIDs := make(map[string]string)
+	content, err := ioutil.ReadFile(externalIDsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading external IDs file %s: %v", externalIDsFile, err)
+	}
+	err = yaml.Unmarshal(content, &externalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse external IDs from file %s: %v", externalIDsFile, err)
+	}
+	return externalIDs, nil
 }
 
 // Function that parses string of role ARNs into array
@@ -1,9 +1,23 @@
 package cloudig
 
 import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	awslocal "github.com/Optum/cloudig/pkg/aws"
+	"github.com/Optum/cloudig/pkg/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/support"
 	"github.com/go-test/deep"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -11,30 +25,30 @@ func TestGetComments(t *testing.T) {
 	comments := []Comments{
 		{
 			AccountID:  "TEST_ACCOUNT1",
-			TAFindings: []map[string]string{{"SECURITY-IAM_Use": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"}, {"FAULT_TOLERANCE-Amazon_EBS_Snapshots": "**EXCEPTION:** We do not persist any critical data on EC2 attached EBS. Data present in these disks are ephemeral in nature"}},
-			ConfigFindings: []map[string]string{
-				{"IAM_POLICY_BLACKLISTED_CHECK": "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"},
-				{"ATTACHED_INTERNET_GATEWAY_CHECK": "**EXCEPTION:** Flags VPCs that have an Internet Gateway attached, Most of our VPC requires IGW enabled in Public subnets as they are web application open to Internet. Better RULE would be to check VPC with all of its SUBNET open to IGW"},
+			TAFindings: []map[string]Comment{{"SECURITY-IAM_Use": {Text: "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"}}, {"FAULT_TOLERANCE-Amazon_EBS_Snapshots": {Text: "**EXCEPTION:** We do not persist any critical data on EC2 attached EBS. Data present in these disks are ephemeral in nature"}}},
+			ConfigFindings: []map[string]Comment{
+				{"IAM_POLICY_BLACKLISTED_CHECK": {Text: "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"}},
+				{"ATTACHED_INTERNET_GATEWAY_CHECK": {Text: "**EXCEPTION:** Flags VPCs that have an Internet Gateway attached, Most of our VPC requires IGW enabled in Public subnets as they are web application open to Internet. Better RULE would be to check VPC with all of its SUBNET open to IGW"}},
 			},
-			HealthReportFindings: []map[string]string{{"AWS_RDS_OPERATIONAL_NOTIFICATION": "**EXCEPTION:** Already taken care."}},
+			HealthReportFindings: []map[string]Comment{{"AWS_RDS_OPERATIONAL_NOTIFICATION": {Text: "**EXCEPTION:** Already taken care."}}},
 		},
 		{
 			AccountID: "TEST_ACCOUNT2",
-			TAFindings: []map[string]string{
-				{"SECURITY-IAM_Use": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"},
-				{"FAULT_TOLERANCE-Amazon_EBS_Snapshots": "**EXCEPTION:** We do not persist any critical data on EC2 attached EBS. Data present in these disks are ephemeral in nature"},
+			TAFindings: []map[string]Comment{
+				{"SECURITY-IAM_Use": {Text: "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"}},
+				{"FAULT_TOLERANCE-Amazon_EBS_Snapshots": {Text: "**EXCEPTION:** We do not persist any critical data on EC2 attached EBS. Data present in these disks are ephemeral in nature"}},
 			},
-			ConfigFindings: []map[string]string{{"IAM_POLICY_BLACKLISTED_CHECK": "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"},
-				{"ATTACHED_INTERNET_GATEWAY_CHECK": "**EXCEPTION:** Flags VPCs that have an Internet Gateway attached, Most of our VPC requires IGW enabled in Public subnets as they are web application open to Internet. Better RULE would be to check VPC with all of its SUBNET open to IGW"},
+			ConfigFindings: []map[string]Comment{{"IAM_POLICY_BLACKLISTED_CHECK": {Text: "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"}},
+				{"ATTACHED_INTERNET_GATEWAY_CHECK": {Text: "**EXCEPTION:** Flags VPCs that have an Internet Gateway attached, Most of our VPC requires IGW enabled in Public subnets as they are web application open to Internet. Better RULE would be to check VPC with all of its SUBNET open to IGW"}},
 			},
-			InspectorReportFindings: []map[string]string{{"CIS_Operating_System_Security_Configuration_Benchmarks-1.0": "**EXCEPTION:** Description here"}},
-			ImageScanFindings: []map[string]string{
-				{"333333333333.dkr.ecr.us-east-1.amazonaws.com/admin/kube-state-metrics:v1.2.0": "EXCEPTION Patch will applied this weekend"},
-				{"ALL:dev": "Still working on it"},
+			InspectorReportFindings: []map[string]Comment{{"CIS_Operating_System_Security_Configuration_Benchmarks-1.0": {Text: "**EXCEPTION:** Description here"}}},
+			ImageScanFindings: []map[string]Comment{
+				{"333333333333.dkr.ecr.us-east-1.amazonaws.com/admin/kube-state-metrics:v1.2.0": {Text: "EXCEPTION Patch will applied this weekend"}},
+				{"ALL:dev": {Text: "Still working on it"}},
 			},
-			ReflectIAMFindings: []map[string]string{
-				{"arn:aws:iam::111111111111:role/eks-worker-dig-green-dev": "**EXCEPTION:** Ignore AccessDenied error. This role doesn't require s3.amazonaws.com/HeadObject access for its functionality"},
-				{"arn:aws:iam::111111111111:role/AWS_111111111111_BreakGlass@someuser@company.com": "**EXCEPTION** this role is used by Jenkins and used as a service principal/account"},
+			ReflectIAMFindings: []map[string]Comment{
+				{"arn:aws:iam::111111111111:role/eks-worker-dig-green-dev": {Text: "**EXCEPTION:** Ignore AccessDenied error. This role doesn't require s3.amazonaws.com/HeadObject access for its functionality"}},
+				{"arn:aws:iam::111111111111:role/AWS_111111111111_BreakGlass@someuser@company.com": {Text: "**EXCEPTION** this role is used by Jenkins and used as a service principal/account"}},
 			},
 		},
 	}
@@ -120,7 +134,7 @@ func TestGetComments(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		actualComments := getComments(comments, c.findingAccount, c.findingType, c.findingName)
+		actualComments := getComments(comments, c.findingAccount, c.findingType, c.findingName).String()
 		if diff := deep.Equal(c.expectedComments, actualComments); diff != nil {
 			t.Fatalf("Expected comments are not correct, the difference is %s", diff)
 		}
@@ -165,28 +179,28 @@ func TestParseCommentsFile(t *testing.T) {
 			expectedOutput: []Comments{
 				{
 					AccountID:               "111111111111",
-					TAFindings:              []map[string]string{{"SECURITY-IAM_Use": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"}},
-					ConfigFindings:          []map[string]string{{"IAM_POLICY_BLACKLISTED_CHECK": "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"}},
-					InspectorReportFindings: []map[string]string{{"CIS_Operating_System_Security_Configuration_Benchmarks-1.0": "**EXCEPTION:** Description here"}},
-					HealthReportFindings:    []map[string]string{{"AWS_RDS_SECURITY_NOTIFICATION": "**EXCEPTION:** Description here"}},
-					ReflectIAMFindings:      []map[string]string{{"arn:aws:iam::111111111111:role/eks-worker-dig-green-dev": "**EXCEPTION:** Ignore AccessDenied error. This role doesn't require s3.amazonaws.com/HeadObject access for its functionality"}},
+					TAFindings:              []map[string]Comment{{"SECURITY-IAM_Use": {Text: "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"}}},
+					ConfigFindings:          []map[string]Comment{{"IAM_POLICY_BLACKLISTED_CHECK": {Text: "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"}}},
+					InspectorReportFindings: []map[string]Comment{{"CIS_Operating_System_Security_Configuration_Benchmarks-1.0": {Text: "**EXCEPTION:** Description here"}}},
+					HealthReportFindings:    []map[string]Comment{{"AWS_RDS_SECURITY_NOTIFICATION": {Text: "**EXCEPTION:** Description here"}}},
+					ReflectIAMFindings:      []map[string]Comment{{"arn:aws:iam::111111111111:role/eks-worker-dig-green-dev": {Text: "**EXCEPTION:** Ignore AccessDenied error. This role doesn't require s3.amazonaws.com/HeadObject access for its functionality"}}},
 				},
 				{
 					AccountID: "222222222222",
-					TAFindings: []map[string]string{
-						{"SECURITY-IAM_Use": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"},
-						{"FAULT_TOLERANCE-Amazon_EBS_Snapshots": "**EXCEPTION:** We do not persist any critical data on EC2 attached EBS. Data present in these disks are ephemeral in nature"},
+					TAFindings: []map[string]Comment{
+						{"SECURITY-IAM_Use": {Text: "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM"}},
+						{"FAULT_TOLERANCE-Amazon_EBS_Snapshots": {Text: "**EXCEPTION:** We do not persist any critical data on EC2 attached EBS. Data present in these disks are ephemeral in nature"}},
 					},
-					ConfigFindings: []map[string]string{
-						{"ATTACHED_INTERNET_GATEWAY_CHECK": "**EXCEPTION:** Flags VPCs that have an Internet Gateway attached, Most of our VPC requires IGW enabled in Public subnets as they are web application open to Internet. Better RULE would be to check VPC with all of its SUBNET open to IGW"},
-						{"IAM_POLICY_BLACKLISTED_CHECK": "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"},
+					ConfigFindings: []map[string]Comment{
+						{"ATTACHED_INTERNET_GATEWAY_CHECK": {Text: "**EXCEPTION:** Flags VPCs that have an Internet Gateway attached, Most of our VPC requires IGW enabled in Public subnets as they are web application open to Internet. Better RULE would be to check VPC with all of its SUBNET open to IGW"}},
+						{"IAM_POLICY_BLACKLISTED_CHECK": {Text: "**EXCEPTION:** Removed the AdminstratorAccess policy since the default AWS_*_Admins uses the policy. Future enhancement would be to create a Custom Rule that no other Role can use the AdmnistratorAccess policy besides the AWS_*_Admins"}},
 					},
 				},
 				{
 					AccountID: "012345678910",
-					ImageScanFindings: []map[string]string{
-						{"012345678910.dkr.ecr.us-east-1.amazonaws.com/app/web-server:prod-canary": "EXCEPTION Patch will applied this weekend"},
-						{"ALL:v1.2.0": "EXCEPTION Patch is coming tomorrow"},
+					ImageScanFindings: []map[string]Comment{
+						{"012345678910.dkr.ecr.us-east-1.amazonaws.com/app/web-server:prod-canary": {Text: "EXCEPTION Patch will applied this weekend"}},
+						{"ALL:v1.2.0": {Text: "EXCEPTION Patch is coming tomorrow"}},
 					},
 				},
 			},
@@ -204,8 +218,234 @@ func TestParseCommentsFile(t *testing.T) {
 	}
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
-			output := parseCommentsFile(tc.file)
+			output := ParseCommentsFile(tc.file)
 			assert.Equal(t, tc.expectedOutput, output)
 		})
 	}
 }
+
+func TestLoadComments(t *testing.T) {
+	override, err := ioutil.ReadFile("../../test/data/comments_override.yaml")
+	assert.NoError(t, err)
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/comments_override.yaml" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(override)
+	}))
+	defer server.Close()
+	defaultClient := commentsHTTPClient
+	commentsHTTPClient = server.Client()
+	defer func() { commentsHTTPClient = defaultClient }()
+
+	testCases := []struct {
+		name             string
+		files            []string
+		expectedComments map[string]string
+		expectedError    error
+	}{
+		{
+			name:  "Return comments of later files first",
+			files: []string{"../../test/data/comments.yaml", "../../test/data/comments_override.yaml"},
+			expectedComments: map[string]string{
+				"111111111111": "**WORK_IN_PROGRESS:** Moving the users to SSO",
+				"222222222222": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM",
+			},
+		},
+		{
+			name:  "Return comments of an https URL",
+			files: []string{"../../test/data/comments.yaml", server.URL + "/comments_override.yaml"},
+			expectedComments: map[string]string{
+				"111111111111": "**WORK_IN_PROGRESS:** Moving the users to SSO",
+				"222222222222": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM",
+			},
+		},
+		{
+			name:  "Return comments of earlier files overridden by later files",
+			files: []string{"../../test/data/comments_override.yaml", "../../test/data/comments.yaml"},
+			expectedComments: map[string]string{
+				"111111111111": "**EXCEPTION:** We use Federation and IAM roles to manage resources in AWS . No users/groups created in IAM",
+			},
+		},
+		{
+			name:          "Return error for an https URL that can't be read",
+			files:         []string{server.URL + "/missing.yaml"},
+			expectedError: fmt.Errorf("error reading comments from %s/missing.yaml: 404 Not Found", server.URL),
+		},
+		{
+			name:          "Return error for an unsupported URL",
+			files:         []string{"ftp://example.com/comments.yaml"},
+			expectedError: errors.New("unsupported comments URL ftp://example.com/comments.yaml, use a local path, s3:// or https://"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			comments, err := LoadComments(nil, tc.files)
+			assert.Equal(t, tc.expectedError, err)
+			for accountID, expected := range tc.expectedComments {
+				assert.Equal(t, expected, getComments(comments, accountID, findingTypeTrustedAdvisor, "SECURITY-IAM_Use").String())
+			}
+		})
+	}
+}
+
+func TestCollectAccountResults(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	clients := make(map[string]awslocal.APIs)
+	for _, accountID := range []string{"333333333333", "111111111111"} {
+		mockAPIs := mocks.NewMockAPIs(mockCtrl)
+		mockAPIs.EXPECT().GetAccountID().Return(accountID, nil)
+		mockAPIs.EXPECT().GetFailingTrustedAdvisorCheckResults().Return(map[*support.TrustedAdvisorCheckDescription]*support.TrustedAdvisorCheckResult{
+			{
+				Category: aws.String("security"),
+				Name:     aws.String("IAM Use"),
+			}: {
+				Status:           aws.String("warning"),
+				ResourcesSummary: &support.TrustedAdvisorResourcesSummary{},
+			},
+		}, nil)
+		clients["arn:aws:iam::"+accountID+":role/cloudig"] = mockAPIs
+	}
+	failingAPIs := mocks.NewMockAPIs(mockCtrl)
+	failingAPIs.EXPECT().GetAccountID().Return("", awserr.New("ExpiredToken", "some error", nil))
+	clients["arn:aws:iam::222222222222:role/cloudig"] = failingAPIs
+
+	accounts := []string{"arn:aws:iam::333333333333:role/cloudig", "arn:aws:iam::222222222222:role/cloudig", "arn:aws:iam::111111111111:role/cloudig", "arn:aws:iam::444444444444:role/cloudig"}
+	report := &TrustedAdvisorReport{}
+	results := collectAccountResults(report, accounts, []string{"us-east-1"}, []Comments{}, 2, func(account, region string) (awslocal.APIs, error) {
+		client, ok := clients[account]
+		if !ok {
+			return nil, errors.New("role can't be assumed")
+		}
+		return client, nil
+	})
+
+	assert.Equal(t, []string{"arn:aws:iam::111111111111:role/cloudig", "arn:aws:iam::222222222222:role/cloudig", "arn:aws:iam::333333333333:role/cloudig", "arn:aws:iam::444444444444:role/cloudig"}, []string{results[0].account, results[1].account, results[2].account, results[3].account})
+
+	reportErrors := mergeAccountResults(report, results)
+	assert.Equal(t, []ReportError{
+		{
+			Account:   "arn:aws:iam::222222222222:role/cloudig",
+			AccountID: "222222222222",
+			Region:    "us-east-1",
+			Report:    "trustedadvisor",
+			Stage:     "getReport",
+			Code:      "ExpiredToken",
+			Message:   "ExpiredToken: some error",
+		},
+		{
+			Account:   "arn:aws:iam::444444444444:role/cloudig",
+			AccountID: "444444444444",
+			Region:    "us-east-1",
+			Report:    "trustedadvisor",
+			Stage:     "assumeRole",
+			Message:   "role can't be assumed",
+		},
+	}, reportErrors)
+	assert.Equal(t, "arn:aws:iam::222222222222:role/cloudig in us-east-1: ExpiredToken: some error", reportErrors[0].Error())
+	assert.Len(t, report.Findings, 2)
+	assert.Equal(t, "111111111111", report.Findings[0].AccountID)
+	assert.Equal(t, "333333333333", report.Findings[1].AccountID)
+}
+
+func TestAccountIDFromRoleARN(t *testing.T) {
+	assert.Equal(t, "111111111111", accountIDFromRoleARN("arn:aws:iam::111111111111:role/cloudig"))
+	assert.Equal(t, "parent", accountIDFromRoleARN("parent"))
+}
+
+func TestCollectAccountResultsMaxConcurrency(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	var inFlight, maxInFlight, calls int32
+	// the first two accounts wait for each other, which only returns when two workers run at the same time
+	bothStarted := make(chan struct{})
+	overlapped := int32(0)
+	accounts := make([]string, 0)
+	for i := 0; i < 6; i++ {
+		accounts = append(accounts, fmt.Sprintf("arn:aws:iam::%012d:role/cloudig", i))
+	}
+	results := collectAccountResults(&TrustedAdvisorReport{}, accounts, []string{"us-east-1"}, []Comments{}, 2, func(account, region string) (awslocal.APIs, error) {
+		mockAPIs := mocks.NewMockAPIs(mockCtrl)
+		mockAPIs.EXPECT().GetAccountID().DoAndReturn(func() (string, error) {
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				max := atomic.LoadInt32(&maxInFlight)
+				if current <= max || atomic.CompareAndSwapInt32(&maxInFlight, max, current) {
+					break
+				}
+			}
+			switch atomic.AddInt32(&calls, 1) {
+			case 1:
+				select {
+				case <-bothStarted:
+					atomic.StoreInt32(&overlapped, 1)
+				case <-time.After(5 * time.Second):
+				}
+			case 2:
+				close(bothStarted)
+			}
+			atomic.AddInt32(&inFlight, -1)
+			return "", errors.New("some error")
+		})
+		return mockAPIs, nil
+	})
+
+	assert.Len(t, results, 6)
+	assert.Equal(t, int32(1), overlapped, "workers should run concurrently")
+	assert.True(t, maxInFlight <= 2, "at most 2 workers should run at a time, got %d", maxInFlight)
+}
+
+func TestCollectAccountResultsRegions(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+
+	accounts := []string{"arn:aws:iam::222222222222:role/cloudig", "arn:aws:iam::111111111111:role/cloudig"}
+	regions := []string{"us-west-2", "us-east-1"}
+	report := &ConfigReport{}
+	results := collectAccountResults(report, accounts, regions, []Comments{}, 0, func(account, region string) (awslocal.APIs, error) {
+		mockAPIs := mocks.NewMockAPIs(mockCtrl)
+		mockAPIs.EXPECT().GetAccountID().Return(accountIDFromRoleARN(account), nil)
+		mockAPIs.EXPECT().GetNonComplaintConfigRules().Return(map[string][]*configservice.EvaluationResult{
+			"S3_BUCKET_VERSIONING_ENABLED": {
+				{
+					ComplianceType: aws.String("NON_COMPLIANT"),
+					EvaluationResultIdentifier: &configservice.EvaluationResultIdentifier{
+						EvaluationResultQualifier: &configservice.EvaluationResultQualifier{
+							ResourceId:   aws.String("bucket-" + region),
+							ResourceType: aws.String("AWS::S3::Bucket"),
+						},
+					},
+				},
+			},
+		}, nil)
+		return mockAPIs, nil
+	})
+	assert.Len(t, results, 4)
+
+	reportErrors := mergeAccountResults(report, results)
+	assert.Empty(t, reportErrors)
+	actual := make([]string, 0)
+	for _, finding := range report.Findings {
+		actual = append(actual, finding.AccountID+"/"+finding.Region+"/"+finding.FlaggedResources["AWS::S3::Bucket"][0])
+	}
+	assert.Equal(t, []string{
+		"111111111111/us-east-1/bucket-us-east-1",
+		"111111111111/us-west-2/bucket-us-west-2",
+		"222222222222/us-east-1/bucket-us-east-1",
+		"222222222222/us-west-2/bucket-us-west-2",
+	}, actual)
+}
+
+func TestParseExternalIDsFile(t *testing.T) {
+	output, err := ParseExternalIDsFile("../../test/data/externalids.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"111111111111": "external-id-1", "222222222222": "external-id-2"}, output)
+
+	_, err = ParseExternalIDsFile("../../test/data/missing.yaml")
+	assert.Error(t, err)
+}
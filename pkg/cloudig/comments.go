@@ -0,0 +1,268 @@
+package cloudig
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Statuses of a structured comment
+const (
+	CommentStatusException      string = "EXCEPTION"
+	CommentStatusWorkInProgress string = "WORK_IN_PROGRESS"
+	CommentStatusAcceptedRisk   string = "ACCEPTED_RISK"
+)
+
+// Comments rendered for findings without a comment, or with an expired comment. Both count as new findings for fail-on
+const (
+	commentNewFinding       string = "NEW_FINDING"
+	commentExpiredException string = "EXPIRED_EXCEPTION"
+)
+
+// commentAllResourcesSuppressed is the comment of a check whose every flagged resource is suppressed
+const commentAllResourcesSuppressed string = "**" + CommentStatusException + ":** All the flagged resources are suppressed"
+
+// commentDateLayout is the layout of the expires date of a comment
+const commentDateLayout string = "2006-01-02"
+
+// Patterns of the accounts and keys of the comments file
+const (
+	commentAllAccounts  string = "*"
+	commentRegexPrefix  string = "regex:"
+	commentECRTagPrefix string = "ALL:"
+)
+
+// commentPatterns caches the compiled key patterns of the comments, keyed by pattern
+var commentPatterns sync.Map
+
+// Comment is the comment of a finding in the comments file, either a plain string or a structured comment:
+//
+//	SECURITY-IAM_Use:
+//	  status: EXCEPTION
+//	  reason: We use Federation and IAM roles
+//	  owner: cloud-team@example.com
+//	  ticket: SEC-123
+//	  expires: "2021-12-31"
+//
+// A comment can also suppress some of the flagged resources of a check with a comment per resource ID. A comment
+// holding only resources leaves the check itself without comment:
+//
+//	S3_BUCKET_LOGGING_ENABLED:
+//	  resources:
+//	    my-logs-bucket: "**EXCEPTION:** This bucket holds the access logs"
+type Comment struct {
+	// Text is the comment given as a plain string
+	Text    string `yaml:"-"`
+	Status  string `yaml:"status"`
+	Reason  string `yaml:"reason"`
+	Owner   string `yaml:"owner"`
+	Ticket  string `yaml:"ticket"`
+	Expires string `yaml:"expires"`
+	// Resources are the comments of the flagged resources by resource ID or pattern
+	Resources map[string]Comment `yaml:"resources"`
+}
+
+// UnmarshalYAML parses a plain string comment into Text, and validates the status and the expires date of a
+// structured comment
+func (c *Comment) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&c.Text); err == nil {
+		return nil
+	}
+	type plain Comment
+	if err := unmarshal((*plain)(c)); err != nil {
+		return err
+	}
+	onlyResources := len(c.Resources) != 0 && c.Reason == "" && c.Owner == "" && c.Ticket == "" && c.Expires == ""
+	switch c.Status {
+	case "":
+		if !onlyResources {
+			c.Status = CommentStatusException
+		}
+	case CommentStatusException, CommentStatusWorkInProgress, CommentStatusAcceptedRisk:
+	default:
+		return fmt.Errorf("invalid comment status '%s'. Options: [%s, %s, %s]", c.Status, CommentStatusException, CommentStatusWorkInProgress, CommentStatusAcceptedRisk)
+	}
+	if c.Expires != "" {
+		if _, err := time.Parse(commentDateLayout, c.Expires); err != nil {
+			return fmt.Errorf("invalid comment expires date '%s'. It should be in the form 'yyyy-mm-dd'", c.Expires)
+		}
+	}
+	return nil
+}
+
+// isExpired tells whether the comment expired before now. A comment expires at the end of its expires date (UTC)
+func (c Comment) isExpired(now time.Time) bool {
+	if c.Expires == "" {
+		return false
+	}
+	expires, err := time.Parse(commentDateLayout, c.Expires)
+	if err != nil {
+		return false
+	}
+	return !now.Before(expires.AddDate(0, 0, 1))
+}
+
+// String renders the comment of a finding: the plain string, "**STATUS:** reason" for a structured comment,
+// EXPIRED_EXCEPTION once it expired and NEW_FINDING when there is no comment
+func (c Comment) String() string {
+	switch {
+	case c.Text != "":
+		return c.Text
+	case c.Status == "":
+		return commentNewFinding
+	case c.isExpired(time.Now()):
+		return commentExpiredException
+	case c.Reason == "":
+		return c.Status
+	default:
+		return "**" + c.Status + ":** " + c.Reason
+	}
+}
+
+// getComments returns the comment of a finding from the comments section with the key findingType, an empty comment
+// rendered as NEW_FINDING when there is none. When several comments apply, the most specific one wins:
+//
+//  1. the exact key in a comments entry of the account (accountid or a list of accounts including it)
+//  2. a key pattern in a comments entry of the account
+//  3. the exact key in a comments entry of every account (accountid "*")
+//  4. a key pattern in a comments entry of every account
+//
+// The first comment of the file wins among comments of the same precedence
+func getComments(comments []Comments, findingAcct string, findingType string, findingName string) Comment {
+	var best Comment
+	bestRank := 0
+	for _, ex := range comments {
+		accountRank := ex.matchAccount(findingAcct)
+		if accountRank == 0 {
+			continue
+		}
+		section := ex.getSection(findingType)
+		rank := accountRank * 2
+		comment, ok := findComment(section, findingName)
+		if !ok {
+			rank--
+			comment, ok = findPatternComment(section, findingType, findingName)
+		}
+		if ok && rank > bestRank {
+			best, bestRank = comment, rank
+		}
+	}
+	return best
+}
+
+// matchAccount returns 2 when the comments are for the account, 1 when they are for every account and 0 otherwise
+func (c Comments) matchAccount(accountID string) int {
+	switch {
+	case c.AccountID == accountID || Contains(c.AccountIDs, accountID):
+		return 2
+	case c.AccountID == commentAllAccounts || Contains(c.AccountIDs, commentAllAccounts):
+		return 1
+	default:
+		return 0
+	}
+}
+
+// accountIDs returns the accounts the comments apply to, "*" for every account
+func (c Comments) accountIDs() []string {
+	if len(c.AccountIDs) != 0 {
+		return c.AccountIDs
+	}
+	return []string{c.AccountID}
+}
+
+// suppressResources returns the flagged resources without the ones suppressed by the resource comments, and the
+// rendered comments of the suppressed resources by resource. An expired resource comment doesn't suppress its resource
+func (c Comment) suppressResources(resources []string) ([]string, map[string]string) {
+	if len(c.Resources) == 0 {
+		return resources, nil
+	}
+	flagged := make([]string, 0, len(resources))
+	var suppressed map[string]string
+	for _, resource := range resources {
+		comment, ok := c.Resources[resource]
+		if !ok {
+			comment, ok = findPatternComment([]map[string]Comment{c.Resources}, "", resource)
+		}
+		if !ok || isNewFinding(comment.String()) {
+			flagged = append(flagged, resource)
+			continue
+		}
+		if suppressed == nil {
+			suppressed = make(map[string]string)
+		}
+		suppressed[resource] = comment.String()
+	}
+	return flagged, suppressed
+}
+
+// findComment returns the comment of key in a comments section
+func findComment(section []map[string]Comment, key string) (Comment, bool) {
+	for _, comments := range section {
+		if comment, ok := comments[key]; ok {
+			return comment, true
+		}
+	}
+	return Comment{}, false
+}
+
+// findPatternComment returns the first comment of a comments section with a key pattern matching key. Keys of the
+// same list item are tried in alphabetical order
+func findPatternComment(section []map[string]Comment, findingType string, key string) (Comment, bool) {
+	for _, comments := range section {
+		patterns := make([]string, 0, len(comments))
+		for pattern := range comments {
+			patterns = append(patterns, pattern)
+		}
+		sort.Strings(patterns)
+		for _, pattern := range patterns {
+			if matchCommentKey(pattern, findingType, key) {
+				return comments[pattern], true
+			}
+		}
+	}
+	return Comment{}, false
+}
+
+// matchCommentKey tells whether a key pattern matches the key of a finding. A pattern is a regular expression
+// prefixed with "regex:", a glob with "*" matching any characters and "?" matching one, or "ALL:<tag>" matching
+// the ECR images with the tag
+func matchCommentKey(pattern string, findingType string, key string) bool {
+	if findingType == findingTypeECRScan && strings.HasPrefix(pattern, commentECRTagPrefix) {
+		tag := strings.Split(key, ":")
+		return len(tag) >= 2 && commentECRTagPrefix+tag[1] == pattern
+	}
+	if !strings.HasPrefix(pattern, commentRegexPrefix) && !strings.ContainsAny(pattern, "*?") {
+		return false
+	}
+	re := compileCommentPattern(pattern)
+	return re != nil && re.MatchString(key)
+}
+
+// compileCommentPattern compiles a key pattern, nil when the regular expression is invalid
+func compileCommentPattern(pattern string) *regexp.Regexp {
+	if re, ok := commentPatterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+	var expr string
+	if strings.HasPrefix(pattern, commentRegexPrefix) {
+		expr = strings.TrimPrefix(pattern, commentRegexPrefix)
+	} else {
+		expr = regexp.QuoteMeta(pattern)
+		expr = strings.NewReplacer(`\*`, ".*", `\?`, ".").Replace(expr)
+		expr = "^" + expr + "$"
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil
+	}
+	commentPatterns.Store(pattern, re)
+	return re
+}
+
+// isNewFinding tells whether a rendered comment is a finding that nobody looked at, or looked at too long ago
+func isNewFinding(comments string) bool {
+	return comments == commentNewFinding || comments == commentExpiredException
+}
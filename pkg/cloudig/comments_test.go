@@ -0,0 +1,297 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCommentUnmarshalYAML(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		expectedOutput map[string]Comment
+		expectedError  error
+	}{
+		{
+			name:           "Return plain string comment",
+			content:        `SECURITY-IAM_Use: "**EXCEPTION:** We use Federation"`,
+			expectedOutput: map[string]Comment{"SECURITY-IAM_Use": {Text: "**EXCEPTION:** We use Federation"}},
+		},
+		{
+			name: "Return structured comment",
+			content: `
+SECURITY-IAM_Use:
+  status: ACCEPTED_RISK
+  reason: We use Federation
+  owner: cloud-team
+  ticket: SEC-123
+  expires: "2021-12-31"
+`,
+			expectedOutput: map[string]Comment{"SECURITY-IAM_Use": {Status: CommentStatusAcceptedRisk, Reason: "We use Federation", Owner: "cloud-team", Ticket: "SEC-123", Expires: "2021-12-31"}},
+		},
+		{
+			name: "Return structured comment with default status",
+			content: `
+SECURITY-IAM_Use:
+  reason: We use Federation
+`,
+			expectedOutput: map[string]Comment{"SECURITY-IAM_Use": {Status: CommentStatusException, Reason: "We use Federation"}},
+		},
+		{
+			name: "Return error for invalid status",
+			content: `
+SECURITY-IAM_Use:
+  status: IGNORED
+`,
+			expectedError: errors.New("invalid comment status 'IGNORED'. Options: [EXCEPTION, WORK_IN_PROGRESS, ACCEPTED_RISK]"),
+		},
+		{
+			name: "Return error for invalid expires date",
+			content: `
+SECURITY-IAM_Use:
+  expires: 12/31/2021
+`,
+			expectedError: errors.New("invalid comment expires date '12/31/2021'. It should be in the form 'yyyy-mm-dd'"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var output map[string]Comment
+			err := yaml.Unmarshal([]byte(tc.content), &output)
+			assert.Equal(t, tc.expectedError, err)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+}
+
+func TestCommentString(t *testing.T) {
+	testCases := []struct {
+		name           string
+		comment        Comment
+		expectedOutput string
+	}{
+		{
+			name:           "Return new finding",
+			comment:        Comment{},
+			expectedOutput: "NEW_FINDING",
+		},
+		{
+			name:           "Return plain string comment",
+			comment:        Comment{Text: "EXCEPTION Patch will applied this weekend"},
+			expectedOutput: "EXCEPTION Patch will applied this weekend",
+		},
+		{
+			name:           "Return status and reason",
+			comment:        Comment{Status: CommentStatusWorkInProgress, Reason: "Patching", Expires: "2999-12-31"},
+			expectedOutput: "**WORK_IN_PROGRESS:** Patching",
+		},
+		{
+			name:           "Return status without reason",
+			comment:        Comment{Status: CommentStatusAcceptedRisk},
+			expectedOutput: "ACCEPTED_RISK",
+		},
+		{
+			name:           "Return expired exception",
+			comment:        Comment{Status: CommentStatusException, Reason: "Temporary", Expires: "2000-01-01"},
+			expectedOutput: "EXPIRED_EXCEPTION",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, tc.comment.String())
+		})
+	}
+}
+
+func TestCommentIsExpired(t *testing.T) {
+	comment := Comment{Status: CommentStatusException, Expires: "2021-12-31"}
+	assert.False(t, comment.isExpired(time.Date(2021, 12, 31, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, comment.isExpired(time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, Comment{Status: CommentStatusException}.isExpired(time.Now()))
+}
+
+func TestGetCommentsPatterns(t *testing.T) {
+	content := `
+- accountid: "*"
+  config-findings:
+    - IAM_PASSWORD_POLICY: "Global exact"
+    - "regex:^IAM_.*": "Global regex"
+  health-findings:
+    - AWS_EC2_*: "Global glob"
+  ecr-findings:
+    - "*/example-app:*": "Global repo glob"
+- accountid: ["111111111111", "222222222222"]
+  config-findings:
+    - IAM_*: "Accounts glob"
+- accountid: "111111111111"
+  config-findings:
+    - IAM_ROOT_ACCESS_KEY_CHECK: "Account exact"
+`
+	var comments []Comments
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &comments))
+	assert.Equal(t, []string{"111111111111", "222222222222"}, comments[1].AccountIDs)
+
+	testCases := []struct {
+		name           string
+		findingAccount string
+		findingType    string
+		findingName    string
+		expectedOutput string
+	}{
+		{
+			name:           "Return exact key of the account",
+			findingAccount: "111111111111",
+			findingType:    findingTypeAWSConfig,
+			findingName:    "IAM_ROOT_ACCESS_KEY_CHECK",
+			expectedOutput: "Account exact",
+		},
+		{
+			name:           "Return key pattern of the account over exact key of every account",
+			findingAccount: "222222222222",
+			findingType:    findingTypeAWSConfig,
+			findingName:    "IAM_PASSWORD_POLICY",
+			expectedOutput: "Accounts glob",
+		},
+		{
+			name:           "Return exact key of every account",
+			findingAccount: "333333333333",
+			findingType:    findingTypeAWSConfig,
+			findingName:    "IAM_PASSWORD_POLICY",
+			expectedOutput: "Global exact",
+		},
+		{
+			name:           "Return regex key of every account",
+			findingAccount: "333333333333",
+			findingType:    findingTypeAWSConfig,
+			findingName:    "IAM_USER_MFA_ENABLED",
+			expectedOutput: "Global regex",
+		},
+		{
+			name:           "Return glob key of every account",
+			findingAccount: "333333333333",
+			findingType:    findingTypeAWSHealth,
+			findingName:    "AWS_EC2_MAINTENANCE_SCHEDULED",
+			expectedOutput: "Global glob",
+		},
+		{
+			name:           "Return repository glob of every account",
+			findingAccount: "333333333333",
+			findingType:    findingTypeECRScan,
+			findingName:    "333333333333.dkr.ecr.us-east-1.amazonaws.com/team/example-app:v1.2.0",
+			expectedOutput: "Global repo glob",
+		},
+		{
+			name:           "Return new finding",
+			findingAccount: "333333333333",
+			findingType:    findingTypeAWSConfig,
+			findingName:    "S3_BUCKET_VERSIONING_ENABLED",
+			expectedOutput: "NEW_FINDING",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, getComments(comments, tc.findingAccount, tc.findingType, tc.findingName).String())
+		})
+	}
+}
+
+func TestSuppressResources(t *testing.T) {
+	content := `
+- accountid: "111111111111"
+  config-findings:
+    - S3_BUCKET_LOGGING_ENABLED:
+        resources:
+          my-logs-bucket: "**EXCEPTION:** This bucket holds the access logs"
+          "*-tfstate-*":
+            status: ACCEPTED_RISK
+            reason: Terraform state
+          old-bucket:
+            reason: Temporary
+            expires: "2000-01-01"
+    - ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK:
+        reason: Public load balancers
+        resources:
+          sg-00001: "**EXCEPTION:** Bastion"
+`
+	var comments []Comments
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &comments))
+
+	testCases := []struct {
+		name               string
+		rule               string
+		resources          []string
+		expectedComments   string
+		expectedFlagged    []string
+		expectedSuppressed map[string]string
+	}{
+		{
+			name:             "Return resources not suppressed",
+			rule:             "S3_BUCKET_LOGGING_ENABLED",
+			resources:        []string{"my-logs-bucket", "old-bucket", "app-bucket"},
+			expectedComments: "NEW_FINDING",
+			expectedFlagged:  []string{"old-bucket", "app-bucket"},
+			expectedSuppressed: map[string]string{
+				"my-logs-bucket": "**EXCEPTION:** This bucket holds the access logs",
+			},
+		},
+		{
+			name:             "Return check fully excepted",
+			rule:             "S3_BUCKET_LOGGING_ENABLED",
+			resources:        []string{"my-logs-bucket", "111111111111-tfstate-prod"},
+			expectedComments: "**EXCEPTION:** All the flagged resources are suppressed",
+			expectedFlagged:  []string{},
+			expectedSuppressed: map[string]string{
+				"my-logs-bucket":            "**EXCEPTION:** This bucket holds the access logs",
+				"111111111111-tfstate-prod": "**ACCEPTED_RISK:** Terraform state",
+			},
+		},
+		{
+			name:               "Return check comment when fully suppressed",
+			rule:               "ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK",
+			resources:          []string{"sg-00001"},
+			expectedComments:   "**EXCEPTION:** Public load balancers",
+			expectedFlagged:    []string{},
+			expectedSuppressed: map[string]string{"sg-00001": "**EXCEPTION:** Bastion"},
+		},
+		{
+			name:             "Return resources of a check without resource comments",
+			rule:             "IAM_PASSWORD_POLICY",
+			resources:        []string{"111111111111"},
+			expectedComments: "NEW_FINDING",
+			expectedFlagged:  []string{"111111111111"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			results := make([]*configservice.EvaluationResult, 0)
+			for _, resource := range tc.resources {
+				results = append(results, &configservice.EvaluationResult{
+					ComplianceType: aws.String(configservice.ComplianceTypeNonCompliant),
+					EvaluationResultIdentifier: &configservice.EvaluationResultIdentifier{
+						EvaluationResultQualifier: &configservice.EvaluationResultQualifier{
+							ConfigRuleName: aws.String(tc.rule),
+							ResourceId:     aws.String(resource),
+							ResourceType:   aws.String("AWS::S3::Bucket"),
+						},
+					},
+				})
+			}
+			output := processConfigResults(map[string][]*configservice.EvaluationResult{tc.rule: results}, ConfigFinding{AccountID: "111111111111"}, comments)
+			assert.Equal(t, tc.expectedComments, output[0].Comments)
+			assert.Equal(t, map[string][]string{"AWS::S3::Bucket": tc.expectedFlagged}, output[0].FlaggedResources)
+			assert.Equal(t, tc.expectedSuppressed, output[0].SuppressedResources)
+		})
+	}
+}
@@ -0,0 +1,112 @@
+package cloudig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	awslocal "github.com/Optum/cloudig/pkg/aws"
+)
+
+// CompositeReport runs several reports against the same client of each account and outputs them as one document
+type CompositeReport struct {
+	Reports []Report
+	// globalRegion is the region the non-regional reports are collected in
+	globalRegion string
+	// errs holds the error of each report when collecting a single account
+	errs []error
+}
+
+// GetReport retrieves every report of the composite for a given account. A report failing doesn't prevent the others
+// from being collected
+func (report *CompositeReport) GetReport(client awslocal.APIs, comments []Comments) error {
+	report.errs = make([]error, len(report.Reports))
+	es := make([]string, 0)
+	for i, r := range report.Reports {
+		// nil when the report is not collected in this region
+		if r == nil {
+			continue
+		}
+		err := r.GetReport(client, comments)
+		if err != nil {
+			report.errs[i] = err
+			es = append(es, fmt.Sprintf("%s: %v", getReportName(r), err))
+		}
+	}
+	if len(es) != 0 {
+		return errors.New(strings.Join(es, "; "))
+	}
+	return nil
+}
+
+// newReport returns the reports to collect in the region. Non-regional reports are only collected in the global region
+func (report *CompositeReport) newReport(region string) Report {
+	reports := make([]Report, len(report.Reports))
+	for i, r := range report.Reports {
+		if r.isRegional() || region == report.globalRegion {
+			reports[i] = r.newReport(region)
+		}
+	}
+	return &CompositeReport{Reports: reports, globalRegion: report.globalRegion}
+}
+
+// mergeReport merges the reports that were successfully collected
+func (report *CompositeReport) mergeReport(r Report) {
+	composite := r.(*CompositeReport)
+	for i, accountReport := range composite.Reports {
+		if accountReport == nil || (composite.errs != nil && composite.errs[i] != nil) {
+			continue
+		}
+		report.Reports[i].mergeReport(accountReport)
+	}
+}
+
+// setErrors sets the errors of each report of the composite. Errors not tied to a report, like a role that can't be
+// assumed, are set on every report
+func (report *CompositeReport) setErrors(reportErrors []ReportError) {
+	for _, r := range report.Reports {
+		name := getReportName(r)
+		errs := make([]ReportError, 0)
+		for _, e := range reportErrors {
+			if e.Report == name || e.Report == getReportName(report) {
+				errs = append(errs, e)
+			}
+		}
+		r.setErrors(errs)
+	}
+}
+
+func (report *CompositeReport) recordCommentKeys(keys *CommentKeys) {
+	for _, r := range report.Reports {
+		r.recordCommentKeys(keys)
+	}
+}
+
+// The composite is regional as soon as one of its reports is
+func (report *CompositeReport) isRegional() bool {
+	for _, r := range report.Reports {
+		if r.isRegional() {
+			return true
+		}
+	}
+	return false
+}
+
+// getReportName returns the name of the report used as section key in the composite output
+func getReportName(report Report) string {
+	if _, ok := report.(*CompositeReport); ok {
+		return "all"
+	}
+	if t, ok := getReportTypeOf(report); ok {
+		return t.Name
+	}
+	return fmt.Sprintf("%T", report)
+}
+
+// getReportTitle returns the title of the report used as section heading in the composite table output
+func getReportTitle(report Report) string {
+	if t, ok := getReportTypeOf(report); ok && t.Title != "" {
+		return t.Title
+	}
+	return getReportName(report)
+}
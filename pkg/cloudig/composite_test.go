@@ -0,0 +1,142 @@
+package cloudig
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Optum/cloudig/pkg/mocks"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/aws/aws-sdk-go/service/support"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeReport_GetReport(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		mockGetConfigRulesError error
+		expectedError           error
+		expectedTAFindingsCount int
+		expectedConfigFindings  int
+	}{
+		{
+			name:                    "Return findings of every report",
+			expectedTAFindingsCount: 1,
+			expectedConfigFindings:  1,
+		},
+		{
+			name:                    "Return findings of the reports that didn't fail",
+			mockGetConfigRulesError: errors.New("some error: 100% of the rules"),
+			expectedError:           errors.New("awsconfig: some error: 100% of the rules"),
+			expectedTAFindingsCount: 1,
+			expectedConfigFindings:  0,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockAPIs := mocks.NewMockAPIs(mockCtrl)
+			mockAPIs.EXPECT().GetAccountID().Return("111111111111", nil).Times(2)
+			mockAPIs.EXPECT().GetFailingTrustedAdvisorCheckResults().Return(map[*support.TrustedAdvisorCheckDescription]*support.TrustedAdvisorCheckResult{
+				{
+					Category: aws.String("security"),
+					Name:     aws.String("IAM Use"),
+				}: {
+					Status:           aws.String("warning"),
+					ResourcesSummary: &support.TrustedAdvisorResourcesSummary{},
+				},
+			}, nil)
+			mockAPIs.EXPECT().GetNonComplaintConfigRules().Return(map[string][]*configservice.EvaluationResult{
+				"S3_BUCKET_VERSIONING_ENABLED": {
+					{
+						ComplianceType: aws.String("NON_COMPLIANT"),
+						EvaluationResultIdentifier: &configservice.EvaluationResultIdentifier{
+							EvaluationResultQualifier: &configservice.EvaluationResultQualifier{
+								ResourceId:   aws.String("bucket"),
+								ResourceType: aws.String("AWS::S3::Bucket"),
+							},
+						},
+					},
+				},
+			}, tc.mockGetConfigRulesError)
+
+			report := &CompositeReport{Reports: []Report{&TrustedAdvisorReport{}, &ConfigReport{}}, globalRegion: "us-east-1"}
+			accountReport := report.newReport("us-east-1")
+			err := accountReport.GetReport(mockAPIs, []Comments{})
+			assert.Equal(t, tc.expectedError, err)
+
+			report.mergeReport(accountReport)
+			assert.Len(t, report.Reports[0].(*TrustedAdvisorReport).Findings, tc.expectedTAFindingsCount)
+			assert.Len(t, report.Reports[1].(*ConfigReport).Findings, tc.expectedConfigFindings)
+		})
+	}
+}
+
+func TestCompositeReport_newReport(t *testing.T) {
+	report := &CompositeReport{Reports: []Report{&TrustedAdvisorReport{}, &ConfigReport{}}, globalRegion: "us-east-1"}
+	assert.True(t, report.isRegional())
+
+	globalReport := report.newReport("us-east-1").(*CompositeReport)
+	assert.Equal(t, &TrustedAdvisorReport{}, globalReport.Reports[0])
+	assert.Equal(t, &ConfigReport{region: "us-east-1"}, globalReport.Reports[1])
+
+	// non-regional reports are only collected in the global region
+	regionalReport := report.newReport("us-west-2").(*CompositeReport)
+	assert.Nil(t, regionalReport.Reports[0])
+	assert.Equal(t, &ConfigReport{region: "us-west-2"}, regionalReport.Reports[1])
+
+	assert.False(t, (&CompositeReport{Reports: []Report{&TrustedAdvisorReport{}}}).isRegional())
+}
+
+func TestCompositeReport_toJSON(t *testing.T) {
+	var report Report = &CompositeReport{Reports: []Report{&TrustedAdvisorReport{}, &ConfigReport{}}}
+	sections := make(map[string]json.RawMessage)
+	err := json.Unmarshal([]byte(report.toJSON(&report)), &sections)
+	assert.NoError(t, err)
+	assert.Contains(t, sections, "reportTime")
+	assert.Contains(t, sections, "trustedadvisor")
+	assert.Contains(t, sections, "awsconfig")
+}
+
+func TestCompositeReport_mergeAccountResults(t *testing.T) {
+	report := &CompositeReport{Reports: []Report{&TrustedAdvisorReport{}, &ConfigReport{}}, globalRegion: "us-east-1"}
+	accountReport := report.newReport("us-east-1").(*CompositeReport)
+	accountReport.Reports[0].(*TrustedAdvisorReport).Findings = []TrustedAdvisorFinding{{AccountID: "111111111111"}}
+	accountReport.errs = []error{nil, awserr.New("NoAvailableConfigurationRecorderException", "no recorder", nil)}
+
+	reportErrors := mergeAccountResults(report, []accountResult{
+		{account: "parent", region: "us-east-1", report: accountReport, stage: stageGetReport, err: errors.New("awsconfig: no recorder")},
+		{account: "arn:aws:iam::222222222222:role/cloudig", region: "us-east-1", report: report.newReport("us-east-1"), stage: stageAssumeRole, err: errors.New("AccessDenied")},
+	})
+	assert.Equal(t, []ReportError{
+		{
+			Account:   "parent",
+			AccountID: "parent",
+			Region:    "us-east-1",
+			Report:    "awsconfig",
+			Stage:     "getReport",
+			Code:      "NoAvailableConfigurationRecorderException",
+			Message:   "NoAvailableConfigurationRecorderException: no recorder",
+		},
+		{
+			Account:   "arn:aws:iam::222222222222:role/cloudig",
+			AccountID: "222222222222",
+			Region:    "us-east-1",
+			Report:    "all",
+			Stage:     "assumeRole",
+			Message:   "AccessDenied",
+		},
+	}, reportErrors)
+	assert.Len(t, report.Reports[0].(*TrustedAdvisorReport).Findings, 1)
+
+	// errors of the composite are set on the report they belong to
+	report.setErrors(reportErrors)
+	assert.Equal(t, []ReportError{reportErrors[1]}, report.Reports[0].(*TrustedAdvisorReport).Errors)
+	assert.Equal(t, reportErrors, report.Reports[1].(*ConfigReport).Errors)
+}
@@ -0,0 +1,149 @@
+package cloudig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	awslocal "github.com/Optum/cloudig/pkg/aws"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultProfileSet is the profile set used when none is selected
+const DefaultProfileSet string = "default"
+
+// Config is the content of the config file. It defines the settings of the accounts and named profile sets of flag values
+type Config struct {
+	// Accounts holds the settings of the accounts by account ID
+	Accounts    map[string]AccountConfig `yaml:"accounts"`
+	ProfileSets map[string]ProfileSet    `yaml:"profile-sets"`
+}
+
+// AccountConfig holds the settings used to assume the role of an account
+type AccountConfig struct {
+	// ViaRoles are the hub roles assumed in order before the role of the account, overriding --via-role. An empty
+	// list assumes the role of the account directly
+	ViaRoles []string `yaml:"via-roles"`
+	// ExternalID overrides --external-id for the account
+	ExternalID string `yaml:"external-id"`
+}
+
+// ProfileSet holds flag values by flag name, ex: "rolearn", "cfile", "output", "regions". Values can be scalars or
+// lists, lists are joined with a comma. A key holding a map is a report section with the values of the flags of that
+// report, keyed by the report name or subcommand, ex: "health" or "iam"
+type ProfileSet map[string]interface{}
+
+// DefaultConfigFile returns the path of the config file used when none is given, ~/.cloudig.yaml
+func DefaultConfigFile() string {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".cloudig.yaml")
+}
+
+// LoadConfig reads and validates the config file
+func LoadConfig(path string) (Config, error) {
+	var config Config
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return config, fmt.Errorf("error reading config file '%s': %v", path, err)
+	}
+	err = yaml.UnmarshalStrict(content, &config)
+	if err != nil {
+		return config, fmt.Errorf("error parsing config file '%s': %v", path, err)
+	}
+	for name, profileSet := range config.ProfileSets {
+		for key, value := range profileSet {
+			if _, ok := value.(map[interface{}]interface{}); !ok {
+				continue
+			}
+			if _, ok := GetReportType("", key); !ok {
+				return config, fmt.Errorf("unknown report '%s' in profile set '%s'", key, name)
+			}
+		}
+	}
+	return config, nil
+}
+
+// GetAssumeRoleOptions returns the options with the hub roles and external IDs of the accounts of the config added.
+// Settings already in the options take precedence
+func (config Config) GetAssumeRoleOptions(options awslocal.AssumeRoleOptions) awslocal.AssumeRoleOptions {
+	externalIDs := make(map[string]string)
+	viaRoles := make(map[string][]string)
+	for accountID, account := range config.Accounts {
+		if account.ExternalID != "" {
+			externalIDs[accountID] = account.ExternalID
+		}
+		if account.ViaRoles != nil {
+			viaRoles[accountID] = account.ViaRoles
+		}
+	}
+	for accountID, externalID := range options.ExternalIDs {
+		externalIDs[accountID] = externalID
+	}
+	for accountID, chain := range options.AccountViaRoles {
+		viaRoles[accountID] = chain
+	}
+	options.ExternalIDs = externalIDs
+	options.AccountViaRoles = viaRoles
+	return options
+}
+
+// GetProfileSet returns the profile set with the given name
+func (config Config) GetProfileSet(name string) (ProfileSet, error) {
+	profileSet, ok := config.ProfileSets[name]
+	if !ok {
+		names := make([]string, 0, len(config.ProfileSets))
+		for n := range config.ProfileSets {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile set '%s'. Options: [%s]", name, strings.Join(names, ", "))
+	}
+	return profileSet, nil
+}
+
+// Flags returns the values of the flags that are not specific to a report
+func (profileSet ProfileSet) Flags() map[string]string {
+	values := make(map[string]string)
+	for key, value := range profileSet {
+		if _, ok := value.(map[interface{}]interface{}); ok {
+			continue
+		}
+		values[key] = flagValue(value)
+	}
+	return values
+}
+
+// ReportFlags returns the values of the flags of a report type, from the section named after its name or subcommand
+func (profileSet ProfileSet) ReportFlags(reportType ReportType) map[string]string {
+	values := make(map[string]string)
+	for key, value := range profileSet {
+		section, ok := value.(map[interface{}]interface{})
+		if !ok || (key != reportType.Name && key != reportType.Use) {
+			continue
+		}
+		for flag, v := range section {
+			values[fmt.Sprint(flag)] = flagValue(v)
+		}
+	}
+	return values
+}
+
+// flagValue converts a value of the config file to the string form of a flag value
+func flagValue(value interface{}) string {
+	list, ok := value.([]interface{})
+	if !ok {
+		return fmt.Sprint(value)
+	}
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		values = append(values, fmt.Sprint(v))
+	}
+	return strings.Join(values, ",")
+}
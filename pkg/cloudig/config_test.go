@@ -0,0 +1,134 @@
+package cloudig
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	awslocal "github.com/Optum/cloudig/pkg/aws"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfig(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		expectedOutput Config
+		expectedError  error
+	}{
+		{
+			name: "Return profile sets",
+			content: `
+accounts:
+  "111111111111":
+    via-roles:
+      - arn:aws:iam::999999999999:role/hub
+    external-id: external-id-1
+  "222222222222":
+    via-roles: []
+profile-sets:
+  prod:
+    rolearn:
+      - arn:aws:iam::111111111111:role/cloudig
+      - arn:aws:iam::222222222222:role/cloudig
+    output: table
+    health:
+      pastdays: 7
+`,
+			expectedOutput: Config{
+				Accounts: map[string]AccountConfig{
+					"111111111111": {ViaRoles: []string{"arn:aws:iam::999999999999:role/hub"}, ExternalID: "external-id-1"},
+					"222222222222": {ViaRoles: []string{}},
+				},
+				ProfileSets: map[string]ProfileSet{
+					"prod": {
+						"rolearn": []interface{}{"arn:aws:iam::111111111111:role/cloudig", "arn:aws:iam::222222222222:role/cloudig"},
+						"output":  "table",
+						"health":  map[interface{}]interface{}{"pastdays": 7},
+					},
+				},
+			},
+		},
+		{
+			name: "Return error for unknown report",
+			content: `
+profile-sets:
+  prod:
+    unknown:
+      pastdays: 7
+`,
+			expectedError: errors.New("unknown report 'unknown' in profile set 'prod'"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "cloudig.yaml")
+			assert.NoError(t, ioutil.WriteFile(path, []byte(tc.content), 0600))
+			output, err := LoadConfig(path)
+			assert.Equal(t, tc.expectedError, err)
+			if tc.expectedError == nil {
+				assert.Equal(t, tc.expectedOutput, output)
+			}
+		})
+	}
+
+	_, err := LoadConfig(filepath.Join(os.TempDir(), "missing", "cloudig.yaml"))
+	assert.Error(t, err)
+}
+
+func TestGetAssumeRoleOptions(t *testing.T) {
+	config := Config{
+		Accounts: map[string]AccountConfig{
+			"111111111111": {ViaRoles: []string{"arn:aws:iam::999999999999:role/hub"}, ExternalID: "external-id-1"},
+			"222222222222": {ViaRoles: []string{}, ExternalID: "external-id-2"},
+			"333333333333": {},
+		},
+	}
+	options := config.GetAssumeRoleOptions(awslocal.AssumeRoleOptions{
+		ExternalID:  "external-id",
+		ExternalIDs: map[string]string{"222222222222": "external-id-file"},
+		ViaRoles:    []string{"arn:aws:iam::888888888888:role/hub"},
+	})
+	assert.Equal(t, awslocal.AssumeRoleOptions{
+		ExternalID:  "external-id",
+		ExternalIDs: map[string]string{"111111111111": "external-id-1", "222222222222": "external-id-file"},
+		ViaRoles:    []string{"arn:aws:iam::888888888888:role/hub"},
+		AccountViaRoles: map[string][]string{
+			"111111111111": {"arn:aws:iam::999999999999:role/hub"},
+			"222222222222": {},
+		},
+	}, options)
+}
+
+func TestGetProfileSet(t *testing.T) {
+	config := Config{ProfileSets: map[string]ProfileSet{"prod": {"output": "table"}, "dev": {}}}
+
+	profileSet, err := config.GetProfileSet("prod")
+	assert.NoError(t, err)
+	assert.Equal(t, ProfileSet{"output": "table"}, profileSet)
+
+	_, err = config.GetProfileSet("test")
+	assert.Equal(t, errors.New("unknown profile set 'test'. Options: [dev, prod]"), err)
+}
+
+func TestProfileSetFlags(t *testing.T) {
+	profileSet := ProfileSet{
+		"rolearn":         []interface{}{"arn:aws:iam::111111111111:role/cloudig", "arn:aws:iam::222222222222:role/cloudig"},
+		"max-concurrency": 5,
+		"health":          map[interface{}]interface{}{"details": true, "exclude-regions": []interface{}{"us-west-1", "us-west-2"}},
+		"iam":             map[interface{}]interface{}{"identity-tags": "team:cloud"},
+	}
+	health, _ := GetReportType(CommandGet, "health")
+	iam, _ := GetReportType(CommandReflect, "iam")
+
+	assert.Equal(t, map[string]string{
+		"rolearn":         "arn:aws:iam::111111111111:role/cloudig,arn:aws:iam::222222222222:role/cloudig",
+		"max-concurrency": "5",
+	}, profileSet.Flags())
+	assert.Equal(t, map[string]string{"details": "true", "exclude-regions": "us-west-1,us-west-2"}, profileSet.ReportFlags(health))
+	assert.Equal(t, map[string]string{"identity-tags": "team:cloud"}, profileSet.ReportFlags(iam))
+}
@@ -0,0 +1,389 @@
+package cloudig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"mime"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	awslocal "github.com/Optum/cloudig/pkg/aws"
+
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/kris-nova/logger"
+)
+
+// Placeholders of the output file and S3 destination names
+const (
+	placeholderDate    string = "{date}"
+	placeholderReport  string = "{report}"
+	placeholderExt     string = "{ext}"
+	placeholderAccount string = "{account}"
+)
+
+// outputS3Scheme prefixes the S3 destination of the output, ex: "s3://bucket/prefix/{date}/{report}.{ext}"
+const outputS3Scheme string = "s3://"
+
+// outputExtensions are the file extensions of the output types. The extension of a template output is the one of its
+// template file without .tmpl
+var outputExtensions = map[string]string{
+	OutputTypeJSON:    "json",
+	OutputTypeTable:   "txt",
+	OutputTypeMDTable: "md",
+	OutputTypeCSV:     "csv",
+	OutputTypeTSV:     "tsv",
+	OutputTypeHTML:    "html",
+	OutputTypeSARIF:   "sarif",
+	OutputTypeJUnit:   "xml",
+}
+
+// OutputOptions tells how the report is rendered and where it is written. The report is printed to stdout when
+// neither File nor S3 is set
+type OutputOptions struct {
+	// Type is the output type, ex: OutputTypeJSON or OutputTypeTemplatePrefix+"./my.tmpl"
+	Type string
+	// File is the path of the local file to write the report to, with placeholders
+	File string
+	// S3 is the s3://bucket/key URL to upload the report to, with placeholders
+	S3 string
+	// SplitByAccount writes a document per account instead of a single document. File and S3 must then contain the
+	// {account} placeholder
+	SplitByAccount bool
+	// SortBy are the keys the rows of the table outputs are sorted by, ex: TableKeySeverity
+	SortBy []string
+	// GroupBy is the key the rows of the table outputs are grouped by, a table per value
+	GroupBy string
+	// AccountIDs are the accounts the report was collected for. When split by account, a document is written for each
+	// of them, including the accounts without findings
+	AccountIDs []string
+}
+
+// outputDocument is a rendered report and the account it is limited to, if any
+type outputDocument struct {
+	accountID string
+	content   string
+}
+
+func (report *TrustedAdvisorReport) forAccount(accountID string) Report {
+	r := *report
+	r.Findings = make([]TrustedAdvisorFinding, 0)
+	for _, finding := range report.Findings {
+		if finding.AccountID == accountID {
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+	return &r
+}
+
+func (report *ConfigReport) forAccount(accountID string) Report {
+	r := *report
+	r.Findings = make([]ConfigFinding, 0)
+	for _, finding := range report.Findings {
+		if finding.AccountID == accountID {
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+	return &r
+}
+
+func (reports *InspectorReports) forAccount(accountID string) Report {
+	r := *reports
+	r.Reports = make([]InspectorReport, 0)
+	for _, report := range reports.Reports {
+		if report.AccountID == accountID {
+			r.Reports = append(r.Reports, report)
+		}
+	}
+	return &r
+}
+
+func (report *HealthReport) forAccount(accountID string) Report {
+	r := *report
+	r.Findings = make([]HealthReportFinding, 0)
+	for _, finding := range report.Findings {
+		if finding.AccountID == accountID {
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+	return &r
+}
+
+func (report *ImageScanReports) forAccount(accountID string) Report {
+	r := *report
+	r.Findings = make([]ImageScanFindings, 0)
+	for _, finding := range report.Findings {
+		if finding.AccountID == accountID {
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+	return &r
+}
+
+func (report *ReflectReport) forAccount(accountID string) Report {
+	r := *report
+	r.Findings = make([]ReflectFinding, 0)
+	for _, finding := range report.Findings {
+		if finding.AccountID == accountID {
+			r.Findings = append(r.Findings, finding)
+		}
+	}
+	return &r
+}
+
+// forAccount limits every report of the composite to the findings of the account
+func (report *CompositeReport) forAccount(accountID string) Report {
+	r := *report
+	r.Reports = make([]Report, 0, len(report.Reports))
+	for _, child := range report.Reports {
+		r.Reports = append(r.Reports, child.forAccount(accountID))
+	}
+	return &r
+}
+
+func (report *TrustedAdvisorReport) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, finding := range report.Findings {
+		accountIDs = appendAccountID(accountIDs, finding.AccountID)
+	}
+	return accountIDs
+}
+
+func (report *ConfigReport) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, finding := range report.Findings {
+		accountIDs = appendAccountID(accountIDs, finding.AccountID)
+	}
+	return accountIDs
+}
+
+func (reports *InspectorReports) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, report := range reports.Reports {
+		accountIDs = appendAccountID(accountIDs, report.AccountID)
+	}
+	return accountIDs
+}
+
+func (report *HealthReport) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, finding := range report.Findings {
+		accountIDs = appendAccountID(accountIDs, finding.AccountID)
+	}
+	return accountIDs
+}
+
+func (report *ImageScanReports) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, finding := range report.Findings {
+		accountIDs = appendAccountID(accountIDs, finding.AccountID)
+	}
+	return accountIDs
+}
+
+func (report *ReflectReport) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, finding := range report.Findings {
+		accountIDs = appendAccountID(accountIDs, finding.AccountID)
+	}
+	return accountIDs
+}
+
+// accountIDs lists the accounts with findings in any report of the composite
+func (report *CompositeReport) accountIDs() []string {
+	accountIDs := make([]string, 0)
+	for _, child := range report.Reports {
+		for _, accountID := range child.accountIDs() {
+			accountIDs = appendAccountID(accountIDs, accountID)
+		}
+	}
+	return accountIDs
+}
+
+// appendAccountID appends an account ID that is not listed yet
+func appendAccountID(accountIDs []string, accountID string) []string {
+	if accountID == "" || Contains(accountIDs, accountID) {
+		return accountIDs
+	}
+	return append(accountIDs, accountID)
+}
+
+// CheckOutputOptions returns an error when the report can't be rendered with the output type, when the sort and group
+// keys are unknown or used with another output than the tables, or when the destinations of the output are invalid
+func CheckOutputOptions(report Report, options OutputOptions) error {
+	if err := CheckOutputType(report, options.Type); err != nil {
+		return err
+	}
+	for _, key := range options.SortBy {
+		if err := checkTableKey(key); err != nil {
+			return err
+		}
+	}
+	if options.GroupBy != "" {
+		if err := checkTableKey(options.GroupBy); err != nil {
+			return err
+		}
+	}
+	if (len(options.SortBy) != 0 || options.GroupBy != "") && options.Type != OutputTypeTable && options.Type != OutputTypeMDTable {
+		return fmt.Errorf("sorting and grouping the rows is only supported by the %s and %s outputs", OutputTypeTable, OutputTypeMDTable)
+	}
+	if options.S3 != "" && (!strings.HasPrefix(options.S3, outputS3Scheme) || !strings.Contains(strings.TrimPrefix(options.S3, outputS3Scheme), "/")) {
+		return fmt.Errorf("invalid S3 destination %s, use s3://bucket/key", options.S3)
+	}
+	if options.SplitByAccount && options.File == "" && options.S3 == "" {
+		return fmt.Errorf("splitting the output by account requires a file or S3 destination")
+	}
+	for _, name := range []string{options.File, options.S3} {
+		if name == "" {
+			continue
+		}
+		hasAccount := strings.Contains(name, placeholderAccount)
+		if options.SplitByAccount && !hasAccount {
+			return fmt.Errorf("%s must contain the %s placeholder when splitting the output by account", name, placeholderAccount)
+		}
+		if !options.SplitByAccount && hasAccount {
+			return fmt.Errorf("the %s placeholder of %s requires splitting the output by account", placeholderAccount, name)
+		}
+	}
+	return nil
+}
+
+// OutputReport renders the report and writes it to the destinations of the options, or prints it to stdout when there
+// are none. When split by account, a document is written per account collected, with findings or errors, sorted by
+// account
+func OutputReport(sess *session.Session, report Report, options OutputOptions, reportErrors []ReportError) error {
+	// logged once rather than per document, table or group rendered
+	logger.Always("report Time: %s", getCurrentTimestamp())
+	if options.File == "" && options.S3 == "" {
+		content, err := renderOutput(report, options, reportErrors)
+		if err != nil {
+			return err
+		}
+		fmt.Println(content)
+		return nil
+	}
+
+	documents := make([]outputDocument, 0)
+	if options.SplitByAccount {
+		for _, accountID := range getReportAccountIDs(report, options.AccountIDs, reportErrors) {
+			content, err := renderOutput(report.forAccount(accountID), options, getAccountErrors(reportErrors, accountID))
+			if err != nil {
+				return err
+			}
+			documents = append(documents, outputDocument{accountID: accountID, content: content})
+		}
+	} else {
+		content, err := renderOutput(report, options, reportErrors)
+		if err != nil {
+			return err
+		}
+		documents = append(documents, outputDocument{content: content})
+	}
+
+	now := time.Now()
+	for _, document := range documents {
+		if options.File != "" {
+			path := expandOutputName(options.File, report, options.Type, document.accountID, now)
+			if err := writeOutputFile(path, document.content); err != nil {
+				return fmt.Errorf("error writing report to %s: %v", path, err)
+			}
+			logger.Success("wrote report to %s", path)
+		}
+		if options.S3 != "" {
+			name := expandOutputName(options.S3, report, options.Type, document.accountID, now)
+			parts := strings.SplitN(strings.TrimPrefix(name, outputS3Scheme), "/", 2)
+			if err := awslocal.PutS3Object(sess, parts[0], parts[1], []byte(document.content), getOutputContentType(options.Type)); err != nil {
+				return fmt.Errorf("error writing report to %s: %v", name, err)
+			}
+			logger.Success("wrote report to %s", name)
+		}
+	}
+	return nil
+}
+
+// renderOutput renders the report with RenderReport, sorting and grouping the rows of the table outputs
+func renderOutput(report Report, options OutputOptions, reportErrors []ReportError) (string, error) {
+	if len(options.SortBy) == 0 && options.GroupBy == "" || options.Type != OutputTypeTable && options.Type != OutputTypeMDTable {
+		return RenderReport(report, options.Type, reportErrors)
+	}
+	return toGroupedTable(sortTableRows(report, options.SortBy), options.Type, options.GroupBy) + errorsToTable(options.Type, reportErrors), nil
+}
+
+// expandOutputName replaces the placeholders of an output file or S3 destination: {date} by the UTC date of the run,
+// {report} by the report name, {ext} by the extension of the output type and {account} by the account ID
+func expandOutputName(name string, report Report, outputType string, accountID string, now time.Time) string {
+	return strings.NewReplacer(
+		placeholderDate, now.UTC().Format("2006-01-02"),
+		placeholderReport, getReportName(report),
+		placeholderExt, getOutputExtension(outputType),
+		placeholderAccount, accountID,
+	).Replace(name)
+}
+
+// getOutputExtension returns the file extension of an output type, ex: "md" for mdtable. The extension of a template
+// output is the one of its template file without .tmpl or .tpl, ex: "html" for report.html.tmpl, txt when it has none
+func getOutputExtension(outputType string) string {
+	if strings.HasPrefix(outputType, OutputTypeTemplatePrefix) {
+		path := strings.TrimPrefix(outputType, OutputTypeTemplatePrefix)
+		path = strings.TrimSuffix(strings.TrimSuffix(path, ".tmpl"), ".tpl")
+		if ext := strings.TrimPrefix(filepath.Ext(path), "."); ext != "" {
+			return ext
+		}
+		return "txt"
+	}
+	if ext, ok := outputExtensions[outputType]; ok {
+		return ext
+	}
+	return outputExtensions[OutputTypeJSON]
+}
+
+// getOutputContentType returns the content type of the S3 object of an output type
+func getOutputContentType(outputType string) string {
+	ext := getOutputExtension(outputType)
+	switch ext {
+	case "sarif", "json":
+		return "application/json"
+	case "md":
+		return "text/markdown; charset=utf-8"
+	}
+	if contentType := mime.TypeByExtension("." + ext); contentType != "" {
+		return contentType
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// getReportAccountIDs returns the IDs of the accounts collected, with findings or with errors, sorted
+func getReportAccountIDs(report Report, collected []string, reportErrors []ReportError) []string {
+	accountIDs := report.accountIDs()
+	for _, accountID := range collected {
+		accountIDs = appendAccountID(accountIDs, accountID)
+	}
+	for _, e := range reportErrors {
+		accountIDs = appendAccountID(accountIDs, e.AccountID)
+	}
+	sort.Strings(accountIDs)
+	return accountIDs
+}
+
+// getAccountErrors returns the errors of an account. Errors without an account ID are in the output of every account
+func getAccountErrors(reportErrors []ReportError, accountID string) []ReportError {
+	errs := make([]ReportError, 0)
+	for _, e := range reportErrors {
+		if e.AccountID == accountID || e.AccountID == "" {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// writeOutputFile writes a document to a local file, creating its directory
+func writeOutputFile(path string, content string) error {
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return err
+		}
+	}
+	return ioutil.WriteFile(path, []byte(content+"\n"), 0644)
+}
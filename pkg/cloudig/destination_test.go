@@ -0,0 +1,170 @@
+package cloudig
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Optum/cloudig/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpandOutputName(t *testing.T) {
+	now := time.Date(2021, 11, 9, 23, 30, 0, 0, time.FixedZone("EST", -5*3600))
+	testCases := []struct {
+		name           string
+		outputName     string
+		report         Report
+		outputType     string
+		accountID      string
+		expectedOutput string
+	}{
+		{
+			name:           "Expand the date in UTC, the report and the extension",
+			outputName:     "s3://bucket/cloudig/{date}/{report}.{ext}",
+			report:         &ImageScanReports{},
+			outputType:     OutputTypeMDTable,
+			expectedOutput: "s3://bucket/cloudig/2021-11-10/ecrscan.md",
+		},
+		{
+			name:           "Expand the account and the name of a composite report",
+			outputName:     "reports/{account}/{report}.{ext}",
+			report:         &CompositeReport{Reports: []Report{&HealthReport{}}},
+			outputType:     OutputTypeJUnit,
+			accountID:      "111111111111",
+			expectedOutput: "reports/111111111111/all.xml",
+		},
+		{
+			name:           "Use the extension of the template file",
+			outputName:     "{report}.{ext}",
+			report:         &TrustedAdvisorReport{},
+			outputType:     OutputTypeTemplatePrefix + "./templates/summary.html.tmpl",
+			expectedOutput: "trustedadvisor.html",
+		},
+		{
+			name:           "Use txt for a template file without extension",
+			outputName:     "{report}.{ext}",
+			report:         &ConfigReport{},
+			outputType:     OutputTypeTemplatePrefix + "summary.tmpl",
+			expectedOutput: "awsconfig.txt",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedOutput, expandOutputName(tc.outputName, tc.report, tc.outputType, tc.accountID, now))
+		})
+	}
+}
+
+func TestCheckOutputOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		options       OutputOptions
+		expectedError error
+	}{
+		{
+			name:    "Accept stdout",
+			options: OutputOptions{Type: OutputTypeJSON},
+		},
+		{
+			name:    "Accept a file and an S3 destination split by account",
+			options: OutputOptions{Type: OutputTypeHTML, File: "{account}.{ext}", S3: "s3://bucket/{date}/{account}.{ext}", SplitByAccount: true},
+		},
+		{
+			name:          "Return the error of the output type",
+			options:       OutputOptions{Type: OutputTypeSARIF, File: "report.sarif"},
+			expectedError: errors.New("sarif output is only supported by the ecrscan and inspector reports"),
+		},
+		{
+			name:          "Return error for an S3 destination without key",
+			options:       OutputOptions{Type: OutputTypeJSON, S3: "s3://bucket"},
+			expectedError: errors.New("invalid S3 destination s3://bucket, use s3://bucket/key"),
+		},
+		{
+			name:          "Return error for an S3 destination without scheme",
+			options:       OutputOptions{Type: OutputTypeJSON, S3: "bucket/report.json"},
+			expectedError: errors.New("invalid S3 destination bucket/report.json, use s3://bucket/key"),
+		},
+		{
+			name:          "Return error when splitting stdout by account",
+			options:       OutputOptions{Type: OutputTypeJSON, SplitByAccount: true},
+			expectedError: errors.New("splitting the output by account requires a file or S3 destination"),
+		},
+		{
+			name:          "Return error when splitting by account without the account placeholder",
+			options:       OutputOptions{Type: OutputTypeJSON, File: "{account}.json", S3: "s3://bucket/report.json", SplitByAccount: true},
+			expectedError: errors.New("s3://bucket/report.json must contain the {account} placeholder when splitting the output by account"),
+		},
+		{
+			name:          "Return error for the account placeholder without splitting by account",
+			options:       OutputOptions{Type: OutputTypeJSON, File: "{account}.json"},
+			expectedError: errors.New("the {account} placeholder of {account}.json requires splitting the output by account"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expectedError, CheckOutputOptions(&HealthReport{}, tc.options))
+		})
+	}
+}
+
+func TestForAccount(t *testing.T) {
+	report := &CompositeReport{Reports: []Report{
+		&HealthReport{Findings: []HealthReportFinding{{AccountID: "111111111111", EventTypeCode: "AWS_EC2_MAINTENANCE"}, {AccountID: "222222222222", EventTypeCode: "AWS_RDS_MAINTENANCE"}}},
+		&InspectorReports{Reports: []InspectorReport{{AccountID: "222222222222", TemplateName: "weekly"}}},
+	}}
+
+	composite := report.forAccount("111111111111").(*CompositeReport)
+	assert.Equal(t, []HealthReportFinding{{AccountID: "111111111111", EventTypeCode: "AWS_EC2_MAINTENANCE"}}, composite.Reports[0].(*HealthReport).Findings)
+	assert.Equal(t, []InspectorReport{}, composite.Reports[1].(*InspectorReports).Reports)
+	// the report itself is left as is
+	assert.Len(t, report.Reports[0].(*HealthReport).Findings, 2)
+}
+
+func TestOutputReportSplitByAccount(t *testing.T) {
+	dir := t.TempDir()
+	report := &ConfigReport{Findings: []ConfigFinding{
+		{AccountID: "222222222222", RuleName: "s3-bucket-logging-enabled", Status: "NON_COMPLIANT", Comments: "NEW_FINDING"},
+		{AccountID: "111111111111", RuleName: "root-mfa-enabled", Status: "NON_COMPLIANT", Comments: "NEW_FINDING"},
+	}}
+	reportErrors := []ReportError{
+		{Account: "arn:aws:iam::333333333333:role/cloudig", AccountID: "333333333333", Message: "AccessDenied"},
+		{Account: "parent", Message: "Throttling"},
+	}
+	// 444444444444 was collected without findings
+	options := OutputOptions{Type: OutputTypeCSV, File: filepath.Join(dir, "{report}", "{account}.{ext}"), SplitByAccount: true, AccountIDs: []string{"111111111111", "444444444444"}}
+
+	assert.NoError(t, OutputReport(nil, report, options, reportErrors))
+	files, err := filepath.Glob(filepath.Join(dir, "awsconfig", "*"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "awsconfig", "111111111111.csv"), filepath.Join(dir, "awsconfig", "222222222222.csv"), filepath.Join(dir, "awsconfig", "333333333333.csv"), filepath.Join(dir, "awsconfig", "444444444444.csv")}, files)
+	content, err := ioutil.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(content), "root-mfa-enabled")
+	assert.NotContains(t, string(content), "s3-bucket-logging-enabled")
+
+	content, err = ioutil.ReadFile(files[3])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(content), "NON_COMPLIANT")
+
+	assert.Equal(t, []ReportError{{Account: "parent", Message: "Throttling"}}, getAccountErrors(reportErrors, "111111111111"))
+}
+
+func TestGetCollectedAccountIDs(t *testing.T) {
+	mockCtrl := gomock.NewController(t)
+	defer mockCtrl.Finish()
+	accounts := []string{"arn:aws:iam::222222222222:role/cloudig", "parent"}
+
+	mockAPIs := mocks.NewMockAPIs(mockCtrl)
+	mockAPIs.EXPECT().GetAccountID().Return("111111111111", nil)
+	assert.Equal(t, []string{"222222222222", "111111111111"}, getCollectedAccountIDs(mockAPIs, accounts))
+
+	// the parent account is left out when its ID can't be resolved
+	mockAPIs.EXPECT().GetAccountID().Return("", errors.New("ExpiredToken"))
+	assert.Equal(t, []string{"222222222222"}, getCollectedAccountIDs(mockAPIs, accounts))
+}
@@ -0,0 +1,24 @@
+/*
+Package cloudig collects findings from cloud sources like Trusted Advisor, AWS Config, Inspector, AWS Health, ECR image
+scans and IAM usage into typed reports, and renders them as JSON, an ASCII table, a markdown table, CSV, TSV, an HTML
+document, a SARIF log, JUnit XML or through a text/template file.
+
+Collecting is separated from rendering so that the reports can be used from other Go programs:
+
+	sess, _ := session.NewSession(aws.NewConfig().WithRegion("us-east-1"))
+	report := &cloudig.ConfigReport{}
+	comments := cloudig.ParseCommentsFile("comments.yaml")
+	reportErrors := cloudig.CollectReport(sess, report, comments, []string{"arn:aws:iam::111111111111:role/cloudig"}, []string{"us-east-1"}, 10, awslocal.AssumeRoleOptions{})
+	for _, finding := range report.Findings {
+		fmt.Println(finding.AccountID, finding.RuleName)
+	}
+	output, _ := cloudig.RenderReport(report, cloudig.OutputTypeJSON, reportErrors)
+	fmt.Println(output)
+
+# Compatibility
+
+The exported report and finding types and their JSON field names are part of the public API. Within a major version,
+fields are only added: existing fields and JSON field names are neither renamed nor removed, and their meaning doesn't
+change. The table outputs are meant for humans and may change in any release.
+*/
+package cloudig
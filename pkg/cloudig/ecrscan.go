@@ -1,12 +1,15 @@
 package cloudig
 
 import (
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
 	awslocal "github.com/Optum/cloudig/pkg/aws"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/kris-nova/logger"
 )
@@ -32,9 +35,41 @@ type ImageScanFindings struct {
 	RepositoryName     string           `json:"repositoryName"`
 	ImageFindingsCount map[string]int64 `json:"imageFindingsCount"`
 	Comments           string           `json:"comments"`
+	Owner              string           `json:"owner,omitempty"`
+	Ticket             string           `json:"ticket,omitempty"`
 	Region             string           `json:"region"`
 }
 
+func init() {
+	RegisterReportType(ReportType{
+		Name:        "ecrscan",
+		Title:       "ECR Image Scan",
+		Command:     CommandGet,
+		Aliases:     []string{"scan", "sc", "s", "ecr"},
+		Short:       "Get ECR Image Scan report findings",
+		CommentsKey: findingTypeECRScan,
+		// repository URI and tag, ex: 111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web-server:v1.2.0
+		CommentsKeyFormat: regexp.MustCompile(`^[^\s/:]+/[^\s:]+:[^\s:]+$`),
+		Report:            &ImageScanReports{},
+		FailOnKeys:        ecrSeverities,
+		CountFindings:     countImageScanFindings,
+		Options: []ReportOption{
+			{Name: "tag", Default: "", Usage: "Tag of ECR image(s) to report scan results."},
+		},
+		Table: func(report Report, tableType string) string {
+			return report.(*ImageScanReports).toTable(tableType)
+		},
+		New: func(options ReportOptions) (Report, error) {
+			return &ImageScanReports{
+				Flags: ImageScanReportFlags{
+					Tag:    options.GetString("tag"),
+					Region: options.Region,
+				},
+			}, nil
+		},
+	})
+}
+
 // GetReport of the vulnerability count of the images of the builds
 func (report *ImageScanReports) GetReport(client awslocal.APIs, comments []Comments) error {
 	start := time.Now()
@@ -57,20 +92,29 @@ func (report *ImageScanReports) GetReport(client awslocal.APIs, comments []Comme
 		return err
 	}
 
-	// Create findings
-	for repo, imageList := range images {
+	// Create findings, by repository name so that the output doesn't change from run to run
+	repos := make([]string, 0, len(images))
+	for repo := range images {
+		repos = append(repos, repo)
+	}
+	sort.Strings(repos)
+	for _, repo := range repos {
+		imageList := images[repo]
 		// If a tag was specified there should only be one image returned per repo
 		if report.Flags.Tag != "" && len(imageList) == 1 {
 			scanFindingCountMap := convertScanFindings(imageList[0])
 			if len(scanFindingCountMap) > 0 {
 				imageURI := repo + ":" + report.Flags.Tag
+				comment := getComments(comments, accountID, findingTypeECRScan, imageURI)
 				scanReport := ImageScanFindings{
 					AccountID:          accountID,
 					ImageDigest:        aws.StringValue(imageList[0].ImageDigest),
 					ImageTag:           report.Flags.Tag,
 					RepositoryName:     aws.StringValue(imageList[0].RepositoryName),
 					ImageFindingsCount: scanFindingCountMap,
-					Comments:           getComments(comments, accountID, findingTypeECRScan, imageURI),
+					Comments:           comment.String(),
+					Owner:              comment.Owner,
+					Ticket:             comment.Ticket,
 					Region:             report.Flags.Region,
 				}
 				report.Findings = append(report.Findings, scanReport)
@@ -82,13 +126,16 @@ func (report *ImageScanReports) GetReport(client awslocal.APIs, comments []Comme
 				scanFindingCountMap := convertScanFindings(image)
 				if len(scanFindingCountMap) > 0 {
 					imageURI := repo + ":" + aws.StringValueSlice(image.ImageTags)[0]
+					comment := getComments(comments, accountID, findingTypeECRScan, imageURI)
 					scanReport := ImageScanFindings{
 						AccountID:          accountID,
 						ImageDigest:        aws.StringValue(image.ImageDigest),
 						ImageTag:           strings.Join(aws.StringValueSlice(image.ImageTags), ","),
 						RepositoryName:     aws.StringValue(image.RepositoryName),
 						ImageFindingsCount: scanFindingCountMap,
-						Comments:           getComments(comments, accountID, findingTypeECRScan, imageURI),
+						Comments:           comment.String(),
+						Owner:              comment.Owner,
+						Ticket:             comment.Ticket,
 						Region:             report.Flags.Region,
 					}
 					report.Findings = append(report.Findings, scanReport)
@@ -102,6 +149,37 @@ func (report *ImageScanReports) GetReport(client awslocal.APIs, comments []Comme
 	return nil
 }
 
+func (report *ImageScanReports) newReport(region string) Report {
+	flags := report.Flags
+	flags.Region = region
+	return &ImageScanReports{Flags: flags}
+}
+
+func (report *ImageScanReports) mergeReport(r Report) {
+	report.Findings = append(report.Findings, r.(*ImageScanReports).Findings...)
+}
+
+func (report *ImageScanReports) isRegional() bool {
+	return true
+}
+
+// recordCommentKeys records the key of the first tag of the images, the one their comment is looked up with
+func (report *ImageScanReports) recordCommentKeys(keys *CommentKeys) {
+	for _, finding := range report.Findings {
+		tag := strings.Split(finding.ImageTag, ",")[0]
+		keys.record(finding.AccountID, findingTypeECRScan, getRepositoryURI(finding)+":"+tag, finding.Comments == commentNewFinding)
+	}
+}
+
+// getRepositoryURI returns the URI of the repository of an image, ex: 111111111111.dkr.ecr.us-east-1.amazonaws.com/app
+func getRepositoryURI(finding ImageScanFindings) string {
+	dnsSuffix := "amazonaws.com"
+	if partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), finding.Region); ok {
+		dnsSuffix = partition.DNSSuffix()
+	}
+	return finding.AccountID + ".dkr.ecr." + finding.Region + "." + dnsSuffix + "/" + finding.RepositoryName
+}
+
 func convertScanFindings(image *ecr.ImageDetail) map[string]int64 {
 	if image != nil && image.ImageScanStatus != nil && aws.StringValue(image.ImageScanStatus.Status) == "COMPLETE" {
 		return aws.Int64ValueMap(image.ImageScanFindingsSummary.FindingSeverityCounts)
@@ -132,7 +132,7 @@ func TestImageScanReports_GetReport(t *testing.T) {
 			mockAPIs.EXPECT().GetAccountID().Return(tc.accountID, tc.getAccountIDError).MaxTimes(1)
 			mockAPIs.EXPECT().GetECRImagesWithTag(tc.tag).Return(tc.getECRImagesWithTagResponse, tc.getECRImagesWithTagResponseError).MaxTimes(1)
 			// Use comments file for testing
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 
 			report := &ImageScanReports{
 				Flags: ImageScanReportFlags{
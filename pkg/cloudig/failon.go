@@ -0,0 +1,271 @@
+package cloudig
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const failOnNewFinding string = "new"
+
+// FailOnCondition is a threshold on the findings of the reports, ex: "ecrscan.CRITICAL>0"
+type FailOnCondition struct {
+	expression string
+	// report is the name of the report the condition applies to, empty for every report
+	report string
+	// key is the severity or the status of the findings to count, commentNewFinding for the new findings
+	key string
+	// the condition is met when the count is greater than threshold
+	threshold int
+}
+
+// ParseFailOn parses a comma separated list of fail-on conditions on the findings of report. A condition is either
+// "new" for any NEW_FINDING or EXPIRED_EXCEPTION, or "<report>.<key>" optionally followed by ">N" or ">=N" (default
+// ">0"). The report is the name, subcommand or alias of a report of the run having FailOnKeys, and the key one of
+// them, ex: "ecrscan.CRITICAL>0", "inspector.High>=10", "awsconfig.NON_COMPLIANT", "trustedadvisor.error". Only the
+// findings without comment or with an expired exception are counted
+func ParseFailOn(report Report, expressions string) ([]FailOnCondition, error) {
+	conditions := make([]FailOnCondition, 0)
+	if expressions == "" {
+		return conditions, nil
+	}
+	for _, expression := range strings.Split(expressions, ",") {
+		expression = strings.TrimSpace(expression)
+		condition := FailOnCondition{expression: expression}
+
+		subject := expression
+		if i := strings.Index(expression, ">"); i != -1 {
+			subject = strings.TrimSpace(expression[:i])
+			value := strings.TrimSpace(expression[i+1:])
+			orEqual := strings.HasPrefix(value, "=")
+			threshold, err := strconv.Atoi(strings.TrimPrefix(value, "="))
+			if err != nil || threshold < 0 {
+				return nil, fmt.Errorf("invalid threshold in fail-on condition '%s'", expression)
+			}
+			if orEqual {
+				threshold--
+			}
+			condition.threshold = threshold
+		}
+
+		if strings.EqualFold(subject, failOnNewFinding) || subject == commentNewFinding {
+			condition.key = commentNewFinding
+			conditions = append(conditions, condition)
+			continue
+		}
+		parts := strings.SplitN(subject, ".", 2)
+		reportType, ok := getFailOnReportType(strings.ToLower(parts[0]), GetReportTypes(""))
+		if len(parts) != 2 || parts[1] == "" || !ok {
+			return nil, fmt.Errorf("invalid fail-on condition '%s'. Use 'new' or '<report>.<key>[>N]' with report one of [%s]", expression, strings.Join(getFailOnReportNames(), ", "))
+		}
+		// the report is looked up in the run first, as aliases can be shared by reports of different commands
+		if reportType, ok = getFailOnReportType(strings.ToLower(parts[0]), getRunReportTypes(report)); !ok {
+			return nil, fmt.Errorf("fail-on condition '%s' is on a report that is not collected", expression)
+		}
+		if !containsFold(reportType.FailOnKeys, parts[1]) {
+			return nil, fmt.Errorf("unknown key '%s' in fail-on condition '%s'. Options for %s: [%s]", parts[1], expression, reportType.Name, strings.Join(reportType.FailOnKeys, ", "))
+		}
+		condition.report = reportType.Name
+		condition.key = parts[1]
+		conditions = append(conditions, condition)
+	}
+	return conditions, nil
+}
+
+// getRunReportTypes returns the report types of a report, of each report of a composite
+func getRunReportTypes(report Report) []ReportType {
+	types := make([]ReportType, 0)
+	if composite, ok := report.(*CompositeReport); ok {
+		for _, r := range composite.Reports {
+			types = append(types, getRunReportTypes(r)...)
+		}
+		return types
+	}
+	if t, ok := getReportTypeOf(report); ok {
+		types = append(types, t)
+	}
+	return types
+}
+
+// getFailOnReportType returns the report type with FailOnKeys of the given name, subcommand or alias
+func getFailOnReportType(name string, types []ReportType) (ReportType, bool) {
+	for _, t := range types {
+		if len(t.FailOnKeys) != 0 && (t.Name == name || t.Use == name || Contains(t.Aliases, name)) {
+			return t, true
+		}
+	}
+	return ReportType{}, false
+}
+
+// getFailOnReportNames returns the names of the report types with FailOnKeys, in the order they were registered
+func getFailOnReportNames() []string {
+	names := make([]string, 0)
+	for _, t := range GetReportTypes("") {
+		if len(t.FailOnKeys) != 0 {
+			names = append(names, t.Name)
+		}
+	}
+	return names
+}
+
+// containsFold tells whether a list contains a value, case insensitively
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckFailOn returns the conditions met by the findings of the report along with the matching count
+func CheckFailOn(report Report, conditions []FailOnCondition) []string {
+	met := make([]string, 0)
+	for _, condition := range conditions {
+		count := countFailOnFindings(report, condition)
+		if count > condition.threshold {
+			met = append(met, fmt.Sprintf("%s (%d)", condition.expression, count))
+		}
+	}
+	return met
+}
+
+// countFailOnFindings counts the findings matching the condition with the CountFindings of the report type
+func countFailOnFindings(report Report, condition FailOnCondition) int {
+	if composite, ok := report.(*CompositeReport); ok {
+		count := 0
+		for _, r := range composite.Reports {
+			count += countFailOnFindings(r, condition)
+		}
+		return count
+	}
+	t, ok := getReportTypeOf(report)
+	if !ok || t.CountFindings == nil || (condition.report != "" && condition.report != t.Name) {
+		return 0
+	}
+	return t.CountFindings(report, condition.key)
+}
+
+// isFailOnFinding tells whether a finding with a status counts for a fail-on key. Only the new findings and expired
+// exceptions count, a comment unblocks the finding whatever the key
+func isFailOnFinding(key string, comments string, status string) bool {
+	if !isNewFinding(comments) {
+		return false
+	}
+	return key == commentNewFinding || strings.EqualFold(status, key)
+}
+
+// countTrustedAdvisorFindings counts the checks with a flagged resource that is not suppressed
+func countTrustedAdvisorFindings(report Report, key string) int {
+	count := 0
+	for _, finding := range report.(*TrustedAdvisorReport).Findings {
+		if isAllResourcesSuppressed(len(finding.FlaggedResources), finding.SuppressedResources) {
+			continue
+		}
+		if isFailOnFinding(key, finding.Comments, finding.Status) {
+			count++
+		}
+	}
+	return count
+}
+
+// countConfigFindings counts the rules with a flagged resource that is not suppressed
+func countConfigFindings(report Report, key string) int {
+	count := 0
+	for _, finding := range report.(*ConfigReport).Findings {
+		flagged := 0
+		for _, resources := range finding.FlaggedResources {
+			flagged += len(resources)
+		}
+		if isAllResourcesSuppressed(flagged, finding.SuppressedResources) {
+			continue
+		}
+		if isFailOnFinding(key, finding.Comments, finding.Status) {
+			count++
+		}
+	}
+	return count
+}
+
+// isAllResourcesSuppressed tells whether every flagged resource of a finding is suppressed in the comments file, the
+// finding is then excepted whatever its status
+func isAllResourcesSuppressed(flagged int, suppressed map[string]string) bool {
+	return flagged == 0 && len(suppressed) != 0
+}
+
+// countInspectorFindings sums the count of the severity over the rule packages without comment
+func countInspectorFindings(report Report, key string) int {
+	count := 0
+	for _, assessmentReport := range report.(*InspectorReports).Reports {
+		for _, finding := range assessmentReport.Findings {
+			if !isNewFinding(finding.Comments) {
+				continue
+			}
+			if key != commentNewFinding {
+				count += getInspectorSeverityCount(finding, key)
+			} else {
+				count++
+			}
+		}
+	}
+	return count
+}
+
+// countImageScanFindings sums the count of the severity over the images without comment
+func countImageScanFindings(report Report, key string) int {
+	count := 0
+	for _, finding := range report.(*ImageScanReports).Findings {
+		if !isNewFinding(finding.Comments) {
+			continue
+		}
+		if key == commentNewFinding {
+			count++
+			continue
+		}
+		for severity, severityCount := range finding.ImageFindingsCount {
+			if strings.EqualFold(severity, key) {
+				count += int(severityCount)
+			}
+		}
+	}
+	return count
+}
+
+func countHealthFindings(report Report, key string) int {
+	count := 0
+	for _, finding := range report.(*HealthReport).Findings {
+		if isFailOnFinding(key, finding.Comments, "") {
+			count++
+		}
+	}
+	return count
+}
+
+func countReflectFindings(report Report, key string) int {
+	count := 0
+	for _, finding := range report.(*ReflectReport).Findings {
+		if isFailOnFinding(key, finding.Comments, "") {
+			count++
+		}
+	}
+	return count
+}
+
+func getInspectorSeverityCount(finding InspectorReportFinding, severity string) int {
+	var value string
+	switch strings.ToLower(severity) {
+	case "high":
+		value = finding.High
+	case "medium":
+		value = finding.Medium
+	case "low":
+		value = finding.Low
+	case "informational":
+		value = finding.Informational
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0
+	}
+	return count
+}
@@ -0,0 +1,167 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFailOn(t *testing.T) {
+	run := &CompositeReport{Reports: []Report{&TrustedAdvisorReport{}, &ConfigReport{}, &InspectorReports{}, &ImageScanReports{}}}
+	testCases := []struct {
+		name           string
+		report         Report
+		expressions    string
+		expectedOutput []FailOnCondition
+		expectedError  error
+	}{
+		{
+			name:           "Return no condition",
+			report:         run,
+			expressions:    "",
+			expectedOutput: []FailOnCondition{},
+		},
+		{
+			name:        "Return conditions",
+			report:      run,
+			expressions: "new, ecr.CRITICAL>0,inspector.High>=10,config.NON_COMPLIANT,config.NON_COMPLIANT > 0",
+			expectedOutput: []FailOnCondition{
+				{expression: "new", key: "NEW_FINDING"},
+				{expression: "ecr.CRITICAL>0", report: "ecrscan", key: "CRITICAL"},
+				{expression: "inspector.High>=10", report: "inspector", key: "High", threshold: 9},
+				{expression: "config.NON_COMPLIANT", report: "awsconfig", key: "NON_COMPLIANT"},
+				{expression: "config.NON_COMPLIANT > 0", report: "awsconfig", key: "NON_COMPLIANT"},
+			},
+		},
+		{
+			name:           "Return the new condition for a report without fail-on keys",
+			report:         &HealthReport{},
+			expressions:    "new",
+			expectedOutput: []FailOnCondition{{expression: "new", key: "NEW_FINDING"}},
+		},
+		{
+			name:          "Return error for unknown report",
+			report:        run,
+			expressions:   "health.open",
+			expectedError: errors.New("invalid fail-on condition 'health.open'. Use 'new' or '<report>.<key>[>N]' with report one of [awsconfig, ecrscan, inspector, trustedadvisor]"),
+		},
+		{
+			name:          "Return error for missing key",
+			report:        run,
+			expressions:   "ecrscan>1",
+			expectedError: errors.New("invalid fail-on condition 'ecrscan>1'. Use 'new' or '<report>.<key>[>N]' with report one of [awsconfig, ecrscan, inspector, trustedadvisor]"),
+		},
+		{
+			name:          "Return error for unknown key",
+			report:        run,
+			expressions:   "ecrscan.CRITCAL>0",
+			expectedError: errors.New("unknown key 'CRITCAL' in fail-on condition 'ecrscan.CRITCAL>0'. Options for ecrscan: [CRITICAL, HIGH, MEDIUM, LOW, INFORMATIONAL, UNDEFINED]"),
+		},
+		{
+			name:          "Return error for a status of another report",
+			report:        run,
+			expressions:   "trustedadvisor.NON_COMPLIANT",
+			expectedError: errors.New("unknown key 'NON_COMPLIANT' in fail-on condition 'trustedadvisor.NON_COMPLIANT'. Options for trustedadvisor: [error, warning]"),
+		},
+		{
+			name:          "Return error for a report that is not collected",
+			report:        &TrustedAdvisorReport{},
+			expressions:   "inspector.High>0",
+			expectedError: errors.New("fail-on condition 'inspector.High>0' is on a report that is not collected"),
+		},
+		{
+			name:          "Return error for invalid threshold",
+			report:        run,
+			expressions:   "ecrscan.CRITICAL>many",
+			expectedError: errors.New("invalid threshold in fail-on condition 'ecrscan.CRITICAL>many'"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := ParseFailOn(tc.report, tc.expressions)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestCheckFailOn(t *testing.T) {
+	report := &CompositeReport{
+		Reports: []Report{
+			&TrustedAdvisorReport{
+				Findings: []TrustedAdvisorFinding{
+					{Status: "warning", Comments: "NEW_FINDING"},
+					{Status: "error", Comments: "**EXCEPTION:** Known exception"},
+					{Status: "error", Comments: commentAllResourcesSuppressed, FlaggedResources: []string{}, SuppressedResources: map[string]string{"i-1": "**EXCEPTION:** Test instance"}},
+				},
+			},
+			&ConfigReport{
+				Findings: []ConfigFinding{
+					{Status: "NON_COMPLIANT", Comments: "NEW_FINDING"},
+					{Status: "NON_COMPLIANT", Comments: "EXPIRED_EXCEPTION"},
+					{Status: "NON_COMPLIANT", Comments: "**EXCEPTION:** Known exception"},
+					{Status: "NON_COMPLIANT", Comments: commentAllResourcesSuppressed, FlaggedResources: map[string][]string{"AWS::S3::Bucket": {}}, SuppressedResources: map[string]string{"bucket": "**EXCEPTION:** Public website"}},
+				},
+			},
+			&InspectorReports{
+				Reports: []InspectorReport{
+					{
+						Findings: []InspectorReportFinding{
+							{High: "2", Medium: "0", Low: "1", Informational: "0", Comments: "NEW_FINDING"},
+							{High: "1", Medium: "0", Low: "0", Informational: "0", Comments: "EXPIRED_EXCEPTION"},
+							{High: "5", Medium: "0", Low: "0", Informational: "0", Comments: "**EXCEPTION:** Known exception"},
+						},
+					},
+				},
+			},
+			&ImageScanReports{
+				Findings: []ImageScanFindings{
+					{ImageFindingsCount: map[string]int64{"HIGH": 3}, Comments: "NEW_FINDING"},
+					{ImageFindingsCount: map[string]int64{"CRITICAL": 1}, Comments: "**EXCEPTION:** Known exception"},
+				},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name           string
+		expressions    string
+		expectedOutput []string
+	}{
+		{
+			name:           "Return new findings and expired exceptions",
+			expressions:    "new",
+			expectedOutput: []string{"new (6)"},
+		},
+		{
+			name:           "Return severities over the threshold",
+			expressions:    "inspector.High>2,inspector.high>=3,inspector.Low>0,ecrscan.HIGH>0",
+			expectedOutput: []string{"inspector.High>2 (3)", "inspector.high>=3 (3)", "inspector.Low>0 (1)", "ecrscan.HIGH>0 (3)"},
+		},
+		{
+			name:           "Return findings having the status",
+			expressions:    "awsconfig.NON_COMPLIANT,trustedadvisor.error,ta.warning",
+			expectedOutput: []string{"awsconfig.NON_COMPLIANT (2)", "ta.warning (1)"},
+		},
+		{
+			name:           "Return conditions with spaces around the threshold",
+			expressions:    "awsconfig.NON_COMPLIANT >1, inspector.High> 2",
+			expectedOutput: []string{"awsconfig.NON_COMPLIANT >1 (2)", "inspector.High> 2 (3)"},
+		},
+		{
+			name:           "Return no condition met by the excepted findings",
+			expressions:    "ecrscan.CRITICAL>0,inspector.High>3",
+			expectedOutput: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conditions, err := ParseFailOn(report, tc.expressions)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, CheckFailOn(report, conditions))
+		})
+	}
+}
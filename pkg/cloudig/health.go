@@ -2,6 +2,7 @@ package cloudig
 
 import (
 	"errors"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -16,23 +17,28 @@ import (
 
 // HealthReport is a struct that contains an array of healthReport
 type HealthReport struct {
-	Findings []healthReportFinding `json:"findings"`
-	Flags    healthReportFlags     `json:"-"` // hide in json output
+	Findings []HealthReportFinding `json:"findings"`
+	Flags    HealthReportFlags     `json:"-"` // hide in json output
 	jsonOutputHelper
 }
 
-type healthReportFlags struct {
+// HealthReportFlags struct specify the format of passed in flags
+type HealthReportFlags struct {
 	Details        bool
 	PastDays       string
 	ExcludeRegions []string
 	IncludeRegions []string
+	Regions        []string
 }
 
-type healthReportFinding struct {
+// HealthReportFinding is an AWS Health event with the entities it affects
+type HealthReportFinding struct {
 	AccountID        string   `json:"accountId"`
 	Arn              string   `json:"arn"`
 	AffectedEntities []string `json:"affectedEntities"`
 	Comments         string   `json:"comments"`
+	Owner            string   `json:"owner,omitempty"`
+	Ticket           string   `json:"ticket,omitempty"`
 	EventTypeCode    string   `json:"eventTypeCode"`
 	LastUpdatedTime  string   `json:"lastUpdatedTime"`
 	Region           string   `json:"region"`
@@ -40,6 +46,48 @@ type healthReportFinding struct {
 	EventDescription string   `json:"eventDescription"`
 }
 
+func init() {
+	RegisterReportType(ReportType{
+		Name:        "health",
+		Title:       "Health",
+		Command:     CommandGet,
+		Aliases:     []string{"he", "h", "healthnotifications", "healthnotification"},
+		Short:       "Get AWS Health notifications' details",
+		CommentsKey: findingTypeAWSHealth,
+		// event type code, ex: AWS_RDS_SECURITY_NOTIFICATION
+		CommentsKeyFormat: regexp.MustCompile(`^AWS_[A-Z0-9_]+$`),
+		Report:            &HealthReport{},
+		CountFindings:     countHealthFindings,
+		Options: []ReportOption{
+			{Name: "details", Shorthand: "d", Default: false, Usage: "Flag to indicate level of printing for each notification (default false)"},
+			{Name: "pastdays", Default: "", Usage: "Number of past days to get results from"},
+			{Name: "exclude-regions", Default: "", Usage: "Set of regions separated by [,] to exclude Health Notifications from. Takes precedence over include-regions"},
+			{Name: "include-regions", Default: "", Usage: "Set of regions separated by [,] to include Health Notifications from. Use \"global\" as a region if wanting notifications affecting all regions. Ignored when exclude-regions is set"},
+		},
+		Table: func(report Report, tableType string) string {
+			return report.(*HealthReport).toTable(tableType)
+		},
+		New: func(options ReportOptions) (Report, error) {
+			report := &HealthReport{Flags: HealthReportFlags{Details: options.GetBool("details"), PastDays: options.GetString("pastdays")}}
+			logger.Debug("all health command flags:\ndetails: %t\npastDays: %s\n", report.Flags.Details, report.Flags.PastDays)
+			excludeRegions, includeRegions := options.GetString("exclude-regions"), options.GetString("include-regions")
+			report.Flags.ExcludeRegions = strings.Split(excludeRegions, ",")
+			if excludeRegions == "" {
+				report.Flags.ExcludeRegions = []string{}
+			}
+			report.Flags.IncludeRegions = strings.Split(includeRegions, ",")
+			if includeRegions == "" {
+				report.Flags.IncludeRegions = []string{}
+			}
+			// health is a global service, --regions filters the events in the API call instead of running the report in each region
+			if len(options.Regions) > 0 {
+				report.Flags.Regions = append(append([]string{}, options.Regions...), "global")
+			}
+			return report, nil
+		},
+	})
+}
+
 // GetReport builds the Inspector report for a given assessment run
 func (report *HealthReport) GetReport(client awslocal.APIs, comments []Comments) error {
 	start := time.Now()
@@ -79,11 +127,14 @@ func (report *HealthReport) GetReport(client awslocal.APIs, comments []Comments)
 		if err != nil {
 			return err
 		}
-		finding := healthReportFinding{
+		comment := getComments(comments, accountID, findingTypeAWSHealth, *details.Event.EventTypeCode)
+		finding := HealthReportFinding{
 			AccountID:        accountID,
 			AffectedEntities: affectedEntities[*details.Event.Arn],
 			Arn:              *details.Event.Arn,
-			Comments:         getComments(comments, accountID, findingTypeAWSHealth, *details.Event.EventTypeCode),
+			Comments:         comment.String(),
+			Owner:            comment.Owner,
+			Ticket:           comment.Ticket,
 			EventTypeCode:    scrubEventTypeCode(*details.Event.EventTypeCode),
 			LastUpdatedTime:  (*details.Event.LastUpdatedTime).String(),
 			Region:           *details.Event.Region,
@@ -103,7 +154,26 @@ func (report *HealthReport) GetReport(client awslocal.APIs, comments []Comments)
 	return nil
 }
 
-func createArnArray(client awslocal.APIs, flags healthReportFlags) ([]*string, error) {
+func (report *HealthReport) newReport(region string) Report {
+	return &HealthReport{Flags: report.Flags}
+}
+
+func (report *HealthReport) mergeReport(r Report) {
+	report.Findings = append(report.Findings, r.(*HealthReport).Findings...)
+}
+
+// AWS Health is a global service, events of all the regions are filtered with the Regions flag instead
+func (report *HealthReport) isRegional() bool {
+	return false
+}
+
+func (report *HealthReport) recordCommentKeys(keys *CommentKeys) {
+	for _, finding := range report.Findings {
+		keys.record(finding.AccountID, findingTypeAWSHealth, unscrubEventTypeCode(finding.EventTypeCode), finding.Comments == commentNewFinding)
+	}
+}
+
+func createArnArray(client awslocal.APIs, flags HealthReportFlags) ([]*string, error) {
 	eventsArray := make([]*health.Event, 0)
 	// Process flags into the event filter as desired
 	if flags.PastDays == "" {
@@ -133,6 +203,10 @@ func createArnArray(client awslocal.APIs, flags healthReportFlags) ([]*string, e
 			},
 		},
 	}
+	// let the API filter the regions instead of dropping the events afterwards
+	if len(flags.Regions) > 0 {
+		eventFilter.Regions = aws.StringSlice(flags.Regions)
+	}
 
 	var nextToken *string
 	for {
@@ -216,6 +290,11 @@ func scrubEventTypeCode(name string) string {
 	return strings.Join(parts, " ")
 }
 
+// unscrubEventTypeCode returns the event type code of a scrubbed one, ex: "Ec2 Operational Issue" => AWS_EC2_OPERATIONAL_ISSUE
+func unscrubEventTypeCode(name string) string {
+	return "AWS_" + strings.ToUpper(strings.Replace(name, " ", "_", -1))
+}
+
 func scrubEventDescription(eventDesc string, details bool) (string, error) {
 	// parse the string as the event description is printed as a raw string,
 	// not rendered
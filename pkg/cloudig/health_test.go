@@ -19,7 +19,7 @@ func TestGetHealthReport(t *testing.T) {
 	testCases := []struct {
 		name               string
 		eventInput         []*string
-		inputFlags         healthReportFlags
+		inputFlags         HealthReportFlags
 		eventFilter        *health.EventFilter
 		eventAPIResponses  []*health.DescribeEventsOutput
 		detailInput        [][]*string
@@ -27,13 +27,13 @@ func TestGetHealthReport(t *testing.T) {
 		entityInputArn     [][]*string
 		entityInputToken   []*string
 		entityAPIResponses []*health.DescribeAffectedEntitiesOutput
-		expectedOutput     []healthReportFinding
+		expectedOutput     []HealthReportFinding
 		expectedError      error
 	}{
 		{
 			name:       "Basic Get Report Run",
 			eventInput: []*string{nil},
-			inputFlags: healthReportFlags{
+			inputFlags: HealthReportFlags{
 				Details:  false,
 				PastDays: "",
 			},
@@ -91,7 +91,7 @@ func TestGetHealthReport(t *testing.T) {
 				},
 			},
 			expectedError: nil,
-			expectedOutput: []healthReportFinding{
+			expectedOutput: []HealthReportFinding{
 				{
 					AccountID:        "account",
 					AffectedEntities: []string{"entity value1"},
@@ -108,7 +108,7 @@ func TestGetHealthReport(t *testing.T) {
 		{
 			name:       "Exclude Region",
 			eventInput: []*string{nil},
-			inputFlags: healthReportFlags{
+			inputFlags: HealthReportFlags{
 				Details:        false,
 				PastDays:       "",
 				ExcludeRegions: []string{"region2"},
@@ -179,7 +179,7 @@ func TestGetHealthReport(t *testing.T) {
 				},
 			},
 			expectedError: nil,
-			expectedOutput: []healthReportFinding{
+			expectedOutput: []HealthReportFinding{
 				{
 					AccountID:        "account",
 					AffectedEntities: []string{"entity value1"},
@@ -196,7 +196,7 @@ func TestGetHealthReport(t *testing.T) {
 		{
 			name:       "Include Region",
 			eventInput: []*string{nil},
-			inputFlags: healthReportFlags{
+			inputFlags: HealthReportFlags{
 				Details:        false,
 				PastDays:       "",
 				IncludeRegions: []string{"region"},
@@ -267,7 +267,7 @@ func TestGetHealthReport(t *testing.T) {
 				},
 			},
 			expectedError: nil,
-			expectedOutput: []healthReportFinding{
+			expectedOutput: []HealthReportFinding{
 				{
 					AccountID:        "account",
 					AffectedEntities: []string{"entity value1"},
@@ -297,7 +297,7 @@ func TestGetHealthReport(t *testing.T) {
 				mockAPIs.EXPECT().GetHealthAffectedEntities(tc.entityInputArn[i], tc.entityInputToken[i]).Return(tc.entityAPIResponses[i], tc.expectedError).MaxTimes(len(tc.entityInputToken))
 			}
 
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 			report := &HealthReport{
 				Flags: tc.inputFlags,
 			}
@@ -312,6 +312,7 @@ func TestGetHealthReport(t *testing.T) {
 func TestCreateArnArray(t *testing.T) {
 	testCases := []struct {
 		name           string
+		flags          HealthReportFlags
 		input          []*string
 		eventFilter    *health.EventFilter
 		apiResponses   []*health.DescribeEventsOutput
@@ -349,6 +350,30 @@ func TestCreateArnArray(t *testing.T) {
 			expectedOutput: []*string{aws.String("arn1"), aws.String("arn2")},
 			expectedError:  nil,
 		},
+		{
+			name:  "Return Events of the regions only",
+			flags: HealthReportFlags{Regions: []string{"us-east-1", "global"}},
+			input: []*string{nil},
+			eventFilter: &health.EventFilter{
+				EventTypeCategories: []*string{aws.String("accountNotification")},
+				EventStatusCodes:    []*string{aws.String("open"), aws.String("upcoming")},
+				LastUpdatedTimes: []*health.DateTimeRange{
+					{},
+				},
+				Regions: []*string{aws.String("us-east-1"), aws.String("global")},
+			},
+			apiResponses: []*health.DescribeEventsOutput{
+				{
+					Events: []*health.Event{
+						{
+							Arn: aws.String("arn1"),
+						},
+					},
+				},
+			},
+			expectedOutput: []*string{aws.String("arn1")},
+			expectedError:  nil,
+		},
 		{
 			name:  "Return error",
 			input: []*string{nil},
@@ -378,7 +403,7 @@ func TestCreateArnArray(t *testing.T) {
 				mockAPIs.EXPECT().GetHealthEvents(tc.eventFilter, tc.input[i]).Return(tc.apiResponses[i], tc.expectedError).MaxTimes(len(tc.input))
 			}
 
-			output, err := createArnArray(mockAPIs, healthReportFlags{})
+			output, err := createArnArray(mockAPIs, tc.flags)
 			assert.Equal(t, tc.expectedOutput, output)
 			assert.Equal(t, tc.expectedError, err)
 		})
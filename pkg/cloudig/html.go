@@ -0,0 +1,248 @@
+package cloudig
+
+import (
+	// embeds the HTML report template
+	_ "embed"
+	"html/template"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kris-nova/logger"
+)
+
+//go:embed templates/report.html
+var htmlReportTemplate string
+
+var htmlReport = template.Must(template.New("report").Parse(htmlReportTemplate))
+
+// markdown of the comments rendered in the HTML output
+var (
+	markdownBold = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	markdownCode = regexp.MustCompile("`([^`]+)`")
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+)
+
+// htmlSection is the table of the findings of a report in the HTML output
+type htmlSection struct {
+	Name    string
+	Title   string
+	Headers []string
+	Rows    []htmlRow
+}
+
+// htmlRow is a finding of an account, New when it has no comment or an expired one
+type htmlRow struct {
+	AccountID string
+	New       bool
+	Cells     []htmlCell
+}
+
+// htmlCell is the rendered value of a column, with the CSS class of its severity if any
+type htmlCell struct {
+	Value template.HTML
+	Class string
+}
+
+// htmlAccountSummary is the number of findings and new findings of an account per section
+type htmlAccountSummary struct {
+	AccountID string
+	Counts    []htmlCount
+}
+
+type htmlCount struct {
+	Findings int
+	New      int
+}
+
+func (report *TrustedAdvisorReport) toHTML() []htmlSection {
+	section := newHTMLSection(report, "Account ID", "Name", "Status", "Flagged Resources", "Comments", "Owner", "Ticket")
+	for _, finding := range report.Findings {
+		flaggedResources := append([]string{"Flagged Count: " + strconv.Itoa(len(finding.FlaggedResources))}, finding.FlaggedResources...)
+		if len(finding.SuppressedResources) != 0 {
+			flaggedResources = append(flaggedResources, "Suppressed Count: "+strconv.Itoa(len(finding.SuppressedResources)))
+		}
+		section.append(finding.AccountID, finding.Comments, textCell(finding.AccountID), textCell(finding.Category, finding.Name), textCell(finding.Status),
+			textCell(flaggedResources...), commentsCell(finding.Comments), textCell(finding.Owner), textCell(finding.Ticket))
+	}
+	return []htmlSection{section.withoutEmptyColumns("Owner", "Ticket")}
+}
+
+func (report *ConfigReport) toHTML() []htmlSection {
+	section := newHTMLSection(report, "Account ID", "Region", "Name", "Flagged Resources", "Comments", "Owner", "Ticket")
+	for _, finding := range report.Findings {
+		flaggedResources := getConfigFlaggedResources(finding)
+		if len(finding.SuppressedResources) != 0 {
+			flaggedResources = append(flaggedResources, "Suppressed Count: "+strconv.Itoa(len(finding.SuppressedResources)))
+		}
+		section.append(finding.AccountID, finding.Comments, textCell(finding.AccountID), textCell(finding.Region), textCell(finding.RuleName),
+			textCell(flaggedResources...), commentsCell(finding.Comments), textCell(finding.Owner), textCell(finding.Ticket))
+	}
+	return []htmlSection{section.withoutEmptyColumns("Region", "Owner", "Ticket")}
+}
+
+func (reports *InspectorReports) toHTML() []htmlSection {
+	section := newHTMLSection(reports, "Account ID", "Region", "Template Name", "Rule Packages", "High", "Medium", "Low", "Informational", "Comments", "Owner", "Ticket")
+	for _, report := range reports.Reports {
+		for _, finding := range report.Findings {
+			section.append(report.AccountID, finding.Comments, textCell(report.AccountID), textCell(report.Region), textCell(report.TemplateName), textCell(finding.RulePackageName),
+				severityCell("high", finding.High), severityCell("medium", finding.Medium), severityCell("low", finding.Low), severityCell("informational", finding.Informational),
+				commentsCell(finding.Comments), textCell(finding.Owner), textCell(finding.Ticket))
+		}
+	}
+	return []htmlSection{section.withoutEmptyColumns("Region", "Owner", "Ticket")}
+}
+
+func (report *HealthReport) toHTML() []htmlSection {
+	section := newHTMLSection(report, "Account ID", "Event Type Code", "Region", "Status Code", "Event Description", "Affected Resources", "Comments", "Owner", "Ticket")
+	for _, finding := range report.Findings {
+		section.append(finding.AccountID, finding.Comments, textCell(finding.AccountID), textCell(finding.EventTypeCode), textCell(finding.Region), textCell(finding.StatusCode),
+			textCell(finding.EventDescription), textCell(finding.AffectedEntities...), commentsCell(finding.Comments), textCell(finding.Owner), textCell(finding.Ticket))
+	}
+	return []htmlSection{section.withoutEmptyColumns("Owner", "Ticket")}
+}
+
+func (report *ImageScanReports) toHTML() []htmlSection {
+	headers := []string{"Account ID", "Region", "Repository Name", "Tag"}
+	for _, severity := range ecrSeverities {
+		headers = append(headers, strings.Title(strings.ToLower(severity)))
+	}
+	section := newHTMLSection(report, append(headers, "Comments", "Owner", "Ticket")...)
+	for _, finding := range report.Findings {
+		cells := []htmlCell{textCell(finding.AccountID), textCell(finding.Region), textCell(finding.RepositoryName), textCell(strings.Split(finding.ImageTag, ",")...)}
+		for _, severity := range ecrSeverities {
+			cells = append(cells, severityCell(strings.ToLower(severity), strconv.FormatInt(finding.ImageFindingsCount[severity], 10)))
+		}
+		section.append(finding.AccountID, finding.Comments, append(cells, commentsCell(finding.Comments), textCell(finding.Owner), textCell(finding.Ticket))...)
+	}
+	return []htmlSection{section.withoutEmptyColumns("Owner", "Ticket")}
+}
+
+func (report *ReflectReport) toHTML() []htmlSection {
+	section := newHTMLSection(report, "Account ID", "Region", "IAM Identity", "Access Details", "Actual Permissions", "Comments", "Owner", "Ticket")
+	for _, finding := range report.Findings {
+		details := make([]string, 0, len(finding.AccessDetails))
+		for _, ad := range finding.AccessDetails {
+			details = append(details, ad.Event+":"+strconv.Itoa(ad.Count))
+		}
+		section.append(finding.AccountID, finding.Comments, textCell(finding.AccountID), textCell(finding.Region), textCell(finding.Identity),
+			textCell(details...), textCell(finding.PermissionSet...), commentsCell(finding.Comments), textCell(finding.Owner), textCell(finding.Ticket))
+	}
+	return []htmlSection{section.withoutEmptyColumns("Region", "Owner", "Ticket")}
+}
+
+// toHTML outputs every report of the composite as its own section
+func (report *CompositeReport) toHTML() []htmlSection {
+	sections := make([]htmlSection, 0, len(report.Reports))
+	for _, child := range report.Reports {
+		sections = append(sections, child.toHTML()...)
+	}
+	return sections
+}
+
+func newHTMLSection(report Report, headers ...string) *htmlSection {
+	return &htmlSection{Name: getReportName(report), Title: getReportTitle(report), Headers: headers, Rows: make([]htmlRow, 0)}
+}
+
+func (section *htmlSection) append(accountID string, comments string, cells ...htmlCell) {
+	section.Rows = append(section.Rows, htmlRow{AccountID: accountID, New: isNewFinding(comments), Cells: cells})
+}
+
+// withoutEmptyColumns removes the optional columns without a value in any row, like the table outputs do for the
+// Region, Owner and Ticket columns
+func (section *htmlSection) withoutEmptyColumns(optional ...string) htmlSection {
+	for i := len(section.Headers) - 1; i >= 0; i-- {
+		if !Contains(optional, section.Headers[i]) {
+			continue
+		}
+		empty := true
+		for _, row := range section.Rows {
+			empty = empty && row.Cells[i].Value == ""
+		}
+		if !empty {
+			continue
+		}
+		section.Headers = append(section.Headers[:i:i], section.Headers[i+1:]...)
+		for j := range section.Rows {
+			section.Rows[j].Cells = append(section.Rows[j].Cells[:i:i], section.Rows[j].Cells[i+1:]...)
+		}
+	}
+	return *section
+}
+
+// textCell escapes the lines of a cell
+func textCell(lines ...string) htmlCell {
+	escaped := make([]string, 0, len(lines))
+	for _, line := range lines {
+		escaped = append(escaped, template.HTMLEscapeString(line))
+	}
+	return htmlCell{Value: template.HTML(strings.Join(escaped, "<br>"))}
+}
+
+// severityCell colors a count of findings of a severity when it is not zero
+func severityCell(severity string, count string) htmlCell {
+	cell := textCell(count)
+	if count = strings.TrimSpace(count); count != "" && count != "0" {
+		cell.Class = "severity-" + severity
+	}
+	return cell
+}
+
+// commentsCell renders the markdown of a comment: bold, code and links
+func commentsCell(comments string) htmlCell {
+	value := template.HTMLEscapeString(comments)
+	value = markdownBold.ReplaceAllString(value, "<strong>$1</strong>")
+	value = markdownCode.ReplaceAllString(value, "<code>$1</code>")
+	value = markdownLink.ReplaceAllString(value, `<a href="$2">$1</a>`)
+	cell := htmlCell{Value: template.HTML(strings.ReplaceAll(value, "\n", "<br>"))}
+	if isNewFinding(comments) {
+		cell.Class = "new-finding"
+	}
+	return cell
+}
+
+// getHTMLAccountSummaries counts the findings and the new findings of each account per section, sorted by account
+func getHTMLAccountSummaries(sections []htmlSection) []htmlAccountSummary {
+	counts := make(map[string][]htmlCount)
+	for i, section := range sections {
+		for _, row := range section.Rows {
+			if counts[row.AccountID] == nil {
+				counts[row.AccountID] = make([]htmlCount, len(sections))
+			}
+			counts[row.AccountID][i].Findings++
+			if row.New {
+				counts[row.AccountID][i].New++
+			}
+		}
+	}
+	summaries := make([]htmlAccountSummary, 0, len(counts))
+	for accountID, accountCounts := range counts {
+		summaries = append(summaries, htmlAccountSummary{AccountID: accountID, Counts: accountCounts})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].AccountID < summaries[j].AccountID })
+	return summaries
+}
+
+// toHTMLDocument renders the sections of a report as a single HTML document with no external assets
+func toHTMLDocument(report Report, reportErrors []ReportError) string {
+	sections := report.toHTML()
+	data := struct {
+		Title      string
+		ReportTime string
+		Sections   []htmlSection
+		Accounts   []htmlAccountSummary
+		Errors     []ReportError
+	}{
+		Title:      getReportTitle(report),
+		ReportTime: getCurrentTimestamp(),
+		Sections:   sections,
+		Accounts:   getHTMLAccountSummaries(sections),
+		Errors:     reportErrors,
+	}
+	var document strings.Builder
+	if err := htmlReport.Execute(&document, data); err != nil {
+		logger.Critical("unable to render the output as HTML: %v", err)
+	}
+	return document.String()
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"regexp"
 	"strings"
 	"time"
 
@@ -16,40 +17,71 @@ import (
 	awslocal "github.com/Optum/cloudig/pkg/aws"
 )
 
-// InspectorReports is a struct that contains an array of inspectorReport
+// inspectorSeverities are the severities of the Inspector findings from the most severe
+var inspectorSeverities = []string{"High", "Medium", "Low", "Informational"}
+
+// InspectorReports is a struct that contains an array of InspectorReport
 type InspectorReports struct {
-	Reports []inspectorReport `json:"reports"`
+	Reports []InspectorReport `json:"reports"`
 	Helper  reportDownloader  `json:"-"`
+	region  string
 	jsonOutputHelper
 }
 
-type inspectorReport struct {
+// InspectorReport is the findings of the latest Inspector assessment run of an account
+type InspectorReport struct {
 	AccountID    string                   `json:"accountId"`
+	Region       string                   `json:"region"`
 	TemplateName string                   `json:"templateName"`
-	Findings     []inspectorReportFinding `json:"findings"`
+	Findings     []InspectorReportFinding `json:"findings"`
 	AMI          map[string]int           `json:"amis"`
 }
 
-type inspectorReportFinding struct {
+// InspectorReportFinding is the count of findings per severity of an Inspector rule package
+type InspectorReportFinding struct {
 	RulePackageName string `json:"rulePackage"`
 	High            string `json:"high"`
 	Medium          string `json:"medium"`
 	Low             string `json:"low"`
 	Informational   string `json:"informational"`
 	Comments        string `json:"comments"`
+	Owner           string `json:"owner,omitempty"`
+	Ticket          string `json:"ticket,omitempty"`
 }
 
 // InspectorHelper is a struct that implements the reportDownloader interface inorder to fake downloading a report for testing scenarios
 type InspectorHelper struct{}
 
 type reportDownloader interface {
-	downloadReport(reportURL string, report inspectorReport) (string, error)
+	downloadReport(reportURL string, report InspectorReport) (string, error)
+}
+
+func init() {
+	RegisterReportType(ReportType{
+		Name:        "inspector",
+		Title:       "Inspector",
+		Command:     CommandGet,
+		Aliases:     []string{"inspect", "ins", "i"},
+		Short:       "Get AWS Inspector report findings",
+		CommentsKey: findingTypeInspector,
+		// rule package name with underscores and its version, ex: Common_Vulnerabilities_and_Exposures-1.1
+		CommentsKeyFormat: regexp.MustCompile(`^\S+-[0-9.]+$`),
+		Report:            &InspectorReports{},
+		FailOnKeys:        inspectorSeverities,
+		CountFindings:     countInspectorFindings,
+		Table: func(report Report, tableType string) string {
+			return report.(*InspectorReports).toTable(tableType)
+		},
+		New: func(options ReportOptions) (Report, error) {
+			return &InspectorReports{Helper: &InspectorHelper{}}, nil
+		},
+	})
 }
 
 // GetReport builds the Inspector report for a given assessment run
 func (reports *InspectorReports) GetReport(client awslocal.APIs, comments []Comments) error {
 	start := time.Now()
-	report := inspectorReport{}
+	report := InspectorReport{}
 
 	// Get accountID from session
 	accountID, err := client.GetAccountID()
@@ -58,6 +90,7 @@ func (reports *InspectorReports) GetReport(client awslocal.APIs, comments []Comm
 	}
 	logger.Info("working on Inspector report for account: %s", accountID)
 	report.AccountID = accountID
+	report.Region = reports.region
 
 	logger.Info("finding most recent assessment run for template(s) in account: %s", accountID)
 	// Get most recent Assessment Run ARNs for each template
@@ -106,8 +139,37 @@ func (reports *InspectorReports) GetReport(client awslocal.APIs, comments []Comm
 	return nil
 }
 
-func getReportFindings(reportFile string, comments []Comments, report inspectorReport) ([]inspectorReportFinding, error) {
-	var reportFindings []inspectorReportFinding
+func (reports *InspectorReports) newReport(region string) Report {
+	return &InspectorReports{Helper: reports.Helper, region: region}
+}
+
+func (reports *InspectorReports) mergeReport(r Report) {
+	reports.Reports = append(reports.Reports, r.(*InspectorReports).Reports...)
+}
+
+func (reports *InspectorReports) isRegional() bool {
+	return true
+}
+
+func (reports *InspectorReports) recordCommentKeys(keys *CommentKeys) {
+	for _, report := range reports.Reports {
+		for _, finding := range report.Findings {
+			// rule packages without findings have no comment
+			if !isZeroFindings(finding) {
+				keys.record(report.AccountID, findingTypeInspector, inspectorCommentKey(finding.RulePackageName), finding.Comments == commentNewFinding)
+			}
+		}
+	}
+}
+
+// inspectorCommentKey returns the comments file key of a rule package
+// ex. CIS Operating System Security Configuration 1.0 => CIS_Operating_System_Security_Configuration-1.0
+func inspectorCommentKey(rulePackageName string) string {
+	return strings.Replace(rulePackageName, " ", "_", -1)
+}
+
+func getReportFindings(reportFile string, comments []Comments, report InspectorReport) ([]InspectorReportFinding, error) {
+	var reportFindings []InspectorReportFinding
 	// Parse report page HTML, build list of findings, then delete report
 	table, err := parseReportTable(reportFile)
 	if err != nil {
@@ -120,42 +182,41 @@ func getReportFindings(reportFile string, comments []Comments, report inspectorR
 	}
 
 	for _, row := range table {
-		inspectorReportFinding := inspectorReportFinding{}
+		reportFinding := InspectorReportFinding{}
 		for index, col := range row {
 			switch index {
 			case 0:
-				inspectorReportFinding.RulePackageName = col
+				reportFinding.RulePackageName = col
 			case 1:
-				inspectorReportFinding.High = col
+				reportFinding.High = col
 			case 2:
-				inspectorReportFinding.Medium = col
+				reportFinding.Medium = col
 			case 3:
-				inspectorReportFinding.Low = col
+				reportFinding.Low = col
 			case 4:
-				inspectorReportFinding.Informational = col
+				reportFinding.Informational = col
 			default:
 				logger.Warning("error parsing finding table from the report")
 			}
 		}
 
-		reportFindings = append(reportFindings, inspectorReportFinding)
+		reportFindings = append(reportFindings, reportFinding)
 	}
 
 	// Get comments for findings
 	for i, finding := range reportFindings {
-		// Format rule package name into comment format
-		// ex. CIS Operating System Security Configuration 1.0 => CIS_Operating_System_Security_Configuration-1.0
-		commentFinding := strings.Replace(finding.RulePackageName, " ", "_", -1)
+		commentFinding := inspectorCommentKey(finding.RulePackageName)
 		reportFindings[i].Comments = ""
 		if !isZeroFindings(finding) {
-			reportFindings[i].Comments = getComments(comments, report.AccountID, findingTypeInspector, commentFinding)
+			comment := getComments(comments, report.AccountID, findingTypeInspector, commentFinding)
+			reportFindings[i].Comments, reportFindings[i].Owner, reportFindings[i].Ticket = comment.String(), comment.Owner, comment.Ticket
 		}
 	}
 
 	return reportFindings, nil
 }
 
-func isZeroFindings(finding inspectorReportFinding) bool {
+func isZeroFindings(finding InspectorReportFinding) bool {
 	if finding.High == "0" && finding.Medium == "0" && finding.Low == "0" && finding.Informational == "0" {
 		return true
 	}
@@ -278,8 +339,13 @@ func downloadFile(filepath string, url string) error {
 	return err
 }
 
-func (helper *InspectorHelper) downloadReport(reportURL string, report inspectorReport) (string, error) {
-	reportFile := "/tmp/inspector_report_" + report.AccountID + ".html"
+func (helper *InspectorHelper) downloadReport(reportURL string, report InspectorReport) (string, error) {
+	// region avoids the regions of the same account overwriting each other's report
+	reportName := report.AccountID
+	if report.Region != "" {
+		reportName += "_" + report.Region
+	}
+	reportFile := "/tmp/inspector_report_" + reportName + ".html"
 	// Download report to tmp folder
 	err := downloadFile(reportFile, reportURL)
 	if err != nil {
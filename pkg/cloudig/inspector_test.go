@@ -19,7 +19,7 @@ import (
 // fakeInspectorHelper is used to fake downloading a report in TestInspectorGetReport
 type fakeInspectorHelper struct{}
 
-func (fakeHelper *fakeInspectorHelper) downloadReport(reportURL string, report inspectorReport) (string, error) {
+func (fakeHelper *fakeInspectorHelper) downloadReport(reportURL string, report InspectorReport) (string, error) {
 	// Create copy of test inspector report for testing so original isn't deleted
 	reportFile := "/tmp/inspector_report_" + report.AccountID + ".html"
 	err := copy("../../test/data/inspector_report_test.html", reportFile)
@@ -37,7 +37,7 @@ func TestInspectorGetReport(t *testing.T) {
 		resourceGroupTags                           map[string]string
 		instancesList                               *ec2.DescribeInstancesOutput
 		imageInformation                            *ec2.DescribeImagesOutput
-		expectedReports                             []inspectorReport
+		expectedReports                             []InspectorReport
 		expectedGetAccountIDError                   error
 		expectedGetMostRecentAssessmentRunInfoError error
 		expectedGenerateReportError                 error
@@ -101,11 +101,11 @@ func TestInspectorGetReport(t *testing.T) {
 					},
 				},
 			},
-			expectedReports: []inspectorReport{
+			expectedReports: []InspectorReport{
 				{
 					AccountID:    "111111111111",
 					TemplateName: "test-once-dev",
-					Findings: []inspectorReportFinding{
+					Findings: []InspectorReportFinding{
 						{
 							RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0",
 							High:            "2581",
@@ -147,7 +147,7 @@ func TestInspectorGetReport(t *testing.T) {
 				{
 					AccountID:    "111111111111",
 					TemplateName: "k8s_weekly_scan",
-					Findings: []inspectorReportFinding{
+					Findings: []InspectorReportFinding{
 						{
 							RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0",
 							High:            "2581",
@@ -278,7 +278,7 @@ func TestInspectorGetReport(t *testing.T) {
 			reports := &InspectorReports{Helper: &fakeInspectorHelper{}}
 
 			// Use fakeInspectorReport's downloadReport method
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 
 			err := reports.GetReport(mockAPIs, comments)
 			assert.Equal(t, tc.expectedReports, reports.Reports)
@@ -435,7 +435,7 @@ func TestDownloadFile(t *testing.T) {
 }
 
 func TestGetReportFindings(t *testing.T) {
-	expectedFindings := []inspectorReportFinding{
+	expectedFindings := []InspectorReportFinding{
 		{
 			RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0",
 			High:            "2581",
@@ -477,8 +477,8 @@ func TestGetReportFindings(t *testing.T) {
 		log.Fatalf("Error copying file: %s\n", err)
 	}
 
-	comments := parseCommentsFile("../../test/data/comments.yaml")
-	report := inspectorReport{AccountID: "111111111111"}
+	comments := ParseCommentsFile("../../test/data/comments.yaml")
+	report := InspectorReport{AccountID: "111111111111"}
 
 	findings, err := getReportFindings(fileName, comments, report)
 	if err != nil {
@@ -0,0 +1,193 @@
+package cloudig
+
+import (
+	"encoding/xml"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kris-nova/logger"
+)
+
+// junitTestCase is a finding of an account in the JUnit output. It fails when the finding is new, and is skipped when
+// it has a comment
+type junitTestCase struct {
+	accountID string
+	report    string
+	name      string
+	comments  string
+	// details describes the finding, ex: the flagged resources
+	details []string
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	ClassName string        `xml:"classname,attr"`
+	Name      string        `xml:"name,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func (report *TrustedAdvisorReport) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		details := append([]string{"Flagged Count: " + strconv.Itoa(len(finding.FlaggedResources))}, finding.FlaggedResources...)
+		cases = append(cases, newTestCase(report, finding.AccountID, finding.Category+"/"+finding.Name, finding.Comments, details...))
+	}
+	return cases
+}
+
+func (report *ConfigReport) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		cases = append(cases, newTestCase(report, finding.AccountID, withTestCaseRegion(finding.RuleName, finding.Region), finding.Comments, getConfigFlaggedResources(finding)...))
+	}
+	return cases
+}
+
+// toTestCases outputs the rule packages with findings, those without have no comment
+func (reports *InspectorReports) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0)
+	for _, report := range reports.Reports {
+		for _, finding := range report.Findings {
+			if isZeroFindings(finding) {
+				continue
+			}
+			details := []string{"High: " + finding.High, "Medium: " + finding.Medium, "Low: " + finding.Low, "Informational: " + finding.Informational}
+			cases = append(cases, newTestCase(reports, report.AccountID, withTestCaseRegion(report.TemplateName+"/"+finding.RulePackageName, report.Region), finding.Comments, details...))
+		}
+	}
+	return cases
+}
+
+func (report *HealthReport) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		details := append([]string{finding.EventDescription}, finding.AffectedEntities...)
+		cases = append(cases, newTestCase(report, finding.AccountID, withTestCaseRegion(finding.EventTypeCode, finding.Region), finding.Comments, details...))
+	}
+	return cases
+}
+
+func (report *ImageScanReports) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		details := make([]string, 0, len(ecrSeverities))
+		for _, severity := range ecrSeverities {
+			details = append(details, fmt.Sprintf("%s: %d", severity, finding.ImageFindingsCount[severity]))
+		}
+		cases = append(cases, newTestCase(report, finding.AccountID, withTestCaseRegion(finding.RepositoryName+":"+finding.ImageTag, finding.Region), finding.Comments, details...))
+	}
+	return cases
+}
+
+func (report *ReflectReport) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		details := make([]string, 0, len(finding.AccessDetails))
+		for _, ad := range finding.AccessDetails {
+			details = append(details, ad.Event+":"+strconv.Itoa(ad.Count))
+		}
+		cases = append(cases, newTestCase(report, finding.AccountID, withTestCaseRegion(finding.Identity, finding.Region), finding.Comments, details...))
+	}
+	return cases
+}
+
+// toTestCases outputs the findings of every report of the composite
+func (report *CompositeReport) toTestCases() []junitTestCase {
+	cases := make([]junitTestCase, 0)
+	for _, child := range report.Reports {
+		cases = append(cases, child.toTestCases()...)
+	}
+	return cases
+}
+
+func newTestCase(report Report, accountID string, name string, comments string, details ...string) junitTestCase {
+	return junitTestCase{accountID: accountID, report: getReportName(report), name: name, comments: comments, details: details}
+}
+
+// withTestCaseRegion suffixes the name of a test case with the region of a regional finding, so that the findings
+// of the regions of an account have distinct names
+func withTestCaseRegion(name string, region string) string {
+	if region == "" {
+		return name
+	}
+	return name + " (" + region + ")"
+}
+
+// toJUnitXML renders the findings of a report as JUnit XML with a test suite per account, sorted by account. New
+// findings are failures, findings with a comment are skipped with the comment as message, and the accounts that
+// couldn't be reported are errors
+func toJUnitXML(report Report, reportErrors []ReportError) string {
+	suites := make(map[string]*junitTestSuite)
+	getSuite := func(accountID string) *junitTestSuite {
+		if suites[accountID] == nil {
+			suites[accountID] = &junitTestSuite{Name: accountID, Cases: make([]junitCase, 0)}
+		}
+		return suites[accountID]
+	}
+
+	for _, testCase := range report.toTestCases() {
+		suite := getSuite(testCase.accountID)
+		c := junitCase{ClassName: testCase.report, Name: testCase.name}
+		message := &junitMessage{Message: testCase.comments, Text: strings.Join(testCase.details, "\n")}
+		if isNewFinding(testCase.comments) {
+			message.Type = testCase.comments
+			c.Failure = message
+			suite.Failures++
+		} else {
+			c.Skipped = message
+			suite.Skipped++
+		}
+		suite.Tests++
+		suite.Cases = append(suite.Cases, c)
+	}
+	for _, e := range reportErrors {
+		suite := getSuite(e.AccountID)
+		suite.Cases = append(suite.Cases, junitCase{ClassName: e.Report, Name: withTestCaseRegion(e.Stage, e.Region), Error: &junitMessage{Message: e.Message, Type: e.Code, Text: e.Account}})
+		suite.Errors++
+		suite.Tests++
+	}
+
+	root := junitTestSuites{Name: "cloudig " + getReportName(report), Suites: make([]junitTestSuite, 0, len(suites))}
+	for _, suite := range suites {
+		root.Suites = append(root.Suites, *suite)
+		root.Tests += suite.Tests
+		root.Failures += suite.Failures
+		root.Errors += suite.Errors
+		root.Skipped += suite.Skipped
+	}
+	sort.Slice(root.Suites, func(i, j int) bool { return root.Suites[i].Name < root.Suites[j].Name })
+
+	content, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		logger.Critical("unable to marshal the output into JUnit XML: %v", err)
+	}
+	return xml.Header + string(content)
+}
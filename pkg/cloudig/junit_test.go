@@ -0,0 +1,89 @@
+package cloudig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJUnitOutput(t *testing.T) {
+	testCases := []struct {
+		name           string
+		report         Report
+		reportErrors   []ReportError
+		expectedOutput string
+	}{
+		{
+			name: "returnTestSuitePerAccount#1",
+			report: &CompositeReport{Reports: []Report{
+				&TrustedAdvisorReport{Findings: []TrustedAdvisorFinding{
+					{AccountID: "222222222222", Category: "SECURITY", Name: "IAM Use", FlaggedResources: []string{"NA"}, Comments: "**EXCEPTION:** We use Federation"},
+					{AccountID: "111111111111", Category: "SECURITY", Name: "MFA on Root Account", Comments: "NEW_FINDING"},
+				}},
+				&ImageScanReports{Findings: []ImageScanFindings{
+					{AccountID: "111111111111", Region: "us-east-1", RepositoryName: "app/web", ImageTag: "v1", ImageFindingsCount: map[string]int64{"HIGH": 2}, Comments: "EXPIRED_EXCEPTION"},
+				}},
+				&ReflectReport{Findings: []ReflectFinding{
+					{AccountID: "111111111111", Identity: "arn:aws:iam::111111111111:role/Read", AccessDetails: []AccessDetails{{"sts.amazonaws.com/AssumeRole", 15}}, Comments: "**WORK_IN_PROGRESS:** Removing <unused> permissions"},
+				}},
+			}},
+			reportErrors: []ReportError{{Account: "arn:aws:iam::333333333333:role/cloudig", AccountID: "333333333333", Region: "us-east-1", Report: "trustedadvisor", Stage: "assumeRole", Code: "AccessDenied", Message: "AccessDenied: not authorized"}},
+			expectedOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="cloudig all" tests="5" failures="2" errors="1" skipped="2">
+  <testsuite name="111111111111" tests="3" failures="2" errors="0" skipped="1">
+    <testcase classname="trustedadvisor" name="SECURITY/MFA on Root Account">
+      <failure message="NEW_FINDING" type="NEW_FINDING">Flagged Count: 0</failure>
+    </testcase>
+    <testcase classname="ecrscan" name="app/web:v1 (us-east-1)">
+      <failure message="EXPIRED_EXCEPTION" type="EXPIRED_EXCEPTION">CRITICAL: 0&#xA;HIGH: 2&#xA;MEDIUM: 0&#xA;LOW: 0&#xA;INFORMATIONAL: 0&#xA;UNDEFINED: 0</failure>
+    </testcase>
+    <testcase classname="reflectiam" name="arn:aws:iam::111111111111:role/Read">
+      <skipped message="**WORK_IN_PROGRESS:** Removing &lt;unused&gt; permissions">sts.amazonaws.com/AssumeRole:15</skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="222222222222" tests="1" failures="0" errors="0" skipped="1">
+    <testcase classname="trustedadvisor" name="SECURITY/IAM Use">
+      <skipped message="**EXCEPTION:** We use Federation">Flagged Count: 1&#xA;NA</skipped>
+    </testcase>
+  </testsuite>
+  <testsuite name="333333333333" tests="1" failures="0" errors="1" skipped="0">
+    <testcase classname="trustedadvisor" name="assumeRole (us-east-1)">
+      <error message="AccessDenied: not authorized" type="AccessDenied">arn:aws:iam::333333333333:role/cloudig</error>
+    </testcase>
+  </testsuite>
+</testsuites>`,
+		},
+		{
+			name: "returnInspectorRulePackagesWithFindings#2",
+			report: &InspectorReports{Reports: []InspectorReport{
+				{AccountID: "111111111111", Region: "us-east-1", TemplateName: "weekly", Findings: []InspectorReportFinding{
+					{RulePackageName: "Common Vulnerabilities and Exposures-1.1", High: "2", Medium: "1", Low: "0", Informational: "0", Comments: "NEW_FINDING"},
+					{RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0", High: "0", Medium: "0", Low: "0", Informational: "0"},
+				}},
+			}},
+			reportErrors: []ReportError{},
+			expectedOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="cloudig inspector" tests="1" failures="1" errors="0" skipped="0">
+  <testsuite name="111111111111" tests="1" failures="1" errors="0" skipped="0">
+    <testcase classname="inspector" name="weekly/Common Vulnerabilities and Exposures-1.1 (us-east-1)">
+      <failure message="NEW_FINDING" type="NEW_FINDING">High: 2&#xA;Medium: 1&#xA;Low: 0&#xA;Informational: 0</failure>
+    </testcase>
+  </testsuite>
+</testsuites>`,
+		},
+		{
+			name:         "returnNoTestSuite#3",
+			report:       &ConfigReport{},
+			reportErrors: []ReportError{},
+			expectedOutput: `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="cloudig awsconfig" tests="0" failures="0" errors="0" skipped="0"></testsuites>`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := RenderReport(tc.report, OutputTypeJUnit, tc.reportErrors)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
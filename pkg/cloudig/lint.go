@@ -0,0 +1,172 @@
+package cloudig
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/kris-nova/logger"
+	"gopkg.in/yaml.v2"
+)
+
+// Kinds of the issues of a comments file found by LintComments
+const (
+	LintIssueParseError     string = "PARSE_ERROR"
+	LintIssueInvalidAccount string = "INVALID_ACCOUNT"
+	LintIssueUnknownSection string = "UNKNOWN_SECTION"
+	LintIssueWrongSection   string = "WRONG_SECTION"
+	LintIssueInvalidKey     string = "INVALID_KEY"
+	LintIssueDuplicateKey   string = "DUPLICATE_KEY"
+	LintIssueStaleKey       string = "STALE_KEY"
+)
+
+// accountIDFormat is the format of the account IDs of the comments file
+var accountIDFormat = regexp.MustCompile(`^[0-9]{12}$`)
+
+// LintIssue is an issue of a comment key of the comments file
+type LintIssue struct {
+	// File is the comments file of the issue, set by the caller linting several files
+	File string `json:"file,omitempty"`
+	// AccountID is the accountid of the comments entry, the accounts separated by a comma for a list of accounts
+	AccountID string `json:"accountId"`
+	Section   string `json:"section"`
+	Key       string `json:"key"`
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+}
+
+// LintComments returns the issues of the content of a comments file: YAML parse errors, invalid accounts, unknown
+// sections, keys with the format of another section or of no section, keys duplicated for an account, and keys
+// matching none of the findings when findings is not nil. Only the keys of the given sections are checked against
+// the findings, the sections of the reports the findings were recorded from, and only for the accounts the findings
+// were collected for
+func LintComments(content []byte, findings *CommentKeys, sections []string) []LintIssue {
+	issues := make([]LintIssue, 0)
+	// a strict parse catches the keys set twice in the same map. Comments can't be parsed strictly, as the sections
+	// it has no field for are kept in Sections
+	var entries []map[string]interface{}
+	if err := yaml.UnmarshalStrict(content, &entries); err != nil {
+		return append(issues, LintIssue{Kind: LintIssueParseError, Message: err.Error()})
+	}
+	var comments []Comments
+	if err := yaml.Unmarshal(content, &comments); err != nil {
+		return append(issues, LintIssue{Kind: LintIssueParseError, Message: err.Error()})
+	}
+
+	seen := make(map[string]bool)
+	for _, entry := range comments {
+		accountIDs := entry.accountIDs()
+		issue := LintIssue{AccountID: strings.Join(accountIDs, ",")}
+		for _, accountID := range accountIDs {
+			if accountID != commentAllAccounts && !accountIDFormat.MatchString(accountID) {
+				issue.Kind, issue.Message = LintIssueInvalidAccount, fmt.Sprintf("accountid '%s' is not a 12 digit account ID or \"*\"", accountID)
+				issues = append(issues, issue)
+			}
+		}
+		for _, section := range entry.sectionKeys() {
+			issue.Section, issue.Key = section, ""
+			reportType, ok := getReportTypeOfCommentsKey(section)
+			if !ok {
+				issue.Kind, issue.Message = LintIssueUnknownSection, fmt.Sprintf("no report has comments section '%s'", section)
+				issues = append(issues, issue)
+				continue
+			}
+			for _, item := range entry.getSection(section) {
+				keys := make([]string, 0, len(item))
+				for key := range item {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					issue.Key = key
+					duplicate := false
+					for _, accountID := range accountIDs {
+						id := accountID + "/" + section + "/" + key
+						duplicate = duplicate || seen[id]
+						seen[id] = true
+					}
+					if duplicate {
+						issue.Kind, issue.Message = LintIssueDuplicateKey, "the key is already commented for the account, the first comment of the file wins"
+						issues = append(issues, issue)
+						continue
+					}
+					if kind, message := lintCommentKey(reportType, key); kind != "" {
+						issue.Kind, issue.Message = kind, message
+						issues = append(issues, issue)
+						continue
+					}
+					if findings != nil && Contains(sections, section) && findings.isCollected(accountIDs) && !hasFinding(findings, accountIDs, section, key) {
+						issue.Kind, issue.Message = LintIssueStaleKey, "the key matches none of the findings of the reports"
+						issues = append(issues, issue)
+					}
+				}
+			}
+		}
+	}
+	return issues
+}
+
+// lintCommentKey returns the kind and message of the issue of a key of the comments section of the report type, an
+// empty kind when the key is valid. Key patterns are only checked to be valid regular expressions
+func lintCommentKey(reportType ReportType, key string) (string, string) {
+	switch {
+	case strings.HasPrefix(key, commentRegexPrefix):
+		if compileCommentPattern(key) == nil {
+			return LintIssueInvalidKey, "the key is not a valid regular expression"
+		}
+		return "", ""
+	case strings.ContainsAny(key, "*?"), reportType.CommentsKey == findingTypeECRScan && strings.HasPrefix(key, commentECRTagPrefix):
+		return "", ""
+	case reportType.CommentsKeyFormat == nil || reportType.CommentsKeyFormat.MatchString(key):
+		return "", ""
+	}
+	others := make([]string, 0)
+	for _, t := range GetReportTypes("") {
+		if t.CommentsKey != reportType.CommentsKey && t.CommentsKeyFormat != nil && t.CommentsKeyFormat.MatchString(key) {
+			others = append(others, t.CommentsKey)
+		}
+	}
+	if len(others) != 0 {
+		return LintIssueWrongSection, fmt.Sprintf("the key has the format of the keys of [%s]", strings.Join(others, ", "))
+	}
+	return LintIssueInvalidKey, fmt.Sprintf("the key doesn't have the format of the keys of %s: %s", reportType.CommentsKey, reportType.CommentsKeyFormat)
+}
+
+// hasFinding tells whether a finding of one of the accounts matches a key
+func hasFinding(findings *CommentKeys, accountIDs []string, section string, key string) bool {
+	for _, accountID := range accountIDs {
+		if findings.hasFinding(accountID, section, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// getReportTypeOfCommentsKey returns the report type with the comments section key
+func getReportTypeOfCommentsKey(key string) (ReportType, bool) {
+	for _, t := range GetReportTypes("") {
+		if t.CommentsKey == key {
+			return t, true
+		}
+	}
+	return ReportType{}, false
+}
+
+// RenderLintIssues renders the issues of a comments file as JSON, an ASCII table, or a markdown table
+func RenderLintIssues(issues []LintIssue, outputType string) string {
+	if outputType != OutputTypeTable && outputType != OutputTypeMDTable {
+		content, err := json.MarshalIndent(map[string][]LintIssue{"issues": issues}, "", "  ")
+		if err != nil {
+			logger.Critical("unable to marshal the output into JSON: %v", err)
+		}
+		return string(content)
+	}
+	table, tableString := getTableWriterWithHeaders(outputType, []string{"File", "Account ID", "Section", "Key", "Kind", "Message"})
+	for _, issue := range issues {
+		table.Append([]string{issue.File, issue.AccountID, issue.Section, issue.Key, issue.Kind, issue.Message})
+	}
+	table.Render()
+	return tableString.String()
+}
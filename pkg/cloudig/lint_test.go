@@ -0,0 +1,228 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLintComments(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		findings       map[string]map[string][]string
+		accounts       []string
+		sections       []string
+		expectedOutput []LintIssue
+	}{
+		{
+			name:    "Return parse error",
+			content: "- accountid: \"111111111111\"\n  ta-findings:\n    - SECURITY-IAM_Use:\n        status: DONE\n",
+			expectedOutput: []LintIssue{
+				{Kind: LintIssueParseError, Message: "invalid comment status 'DONE'. Options: [EXCEPTION, WORK_IN_PROGRESS, ACCEPTED_RISK]"},
+			},
+		},
+		{
+			name:    "Return parse error for a key twice in the same map",
+			content: "- accountid: \"111111111111\"\n  ta-findings:\n    - SECURITY-IAM_Use: \"a\"\n      SECURITY-IAM_Use: \"b\"\n",
+			expectedOutput: []LintIssue{
+				{Kind: LintIssueParseError, Message: "yaml: unmarshal errors:\n  line 4: key \"SECURITY-IAM_Use\" already set in map"},
+			},
+		},
+		{
+			name: "Return format issues",
+			content: `
+- accountid: "11111111111"
+  ta-findings:
+    - SECURITY-IAM_Use: "**EXCEPTION:** We use Federation"
+    - AWS_EC2_OPERATIONAL_ISSUE: "**EXCEPTION:** Handled by the auto scaling groups"
+  health-findings:
+    - "regex:AWS_(EC2": "**EXCEPTION:** Invalid regular expression"
+    - AWS_EC2_*: "**EXCEPTION:** Handled by the auto scaling groups"
+    - rds security notification: "**EXCEPTION:** Not a key"
+  ecr-findings:
+    - ALL:v1.2.0: "**EXCEPTION:** Patch is coming"
+  ta-finding:
+    - SECURITY-IAM_Use: "**EXCEPTION:** Unknown section"
+`,
+			expectedOutput: []LintIssue{
+				{AccountID: "11111111111", Kind: LintIssueInvalidAccount, Message: "accountid '11111111111' is not a 12 digit account ID or \"*\""},
+				{AccountID: "11111111111", Section: "ta-findings", Key: "AWS_EC2_OPERATIONAL_ISSUE", Kind: LintIssueWrongSection, Message: "the key has the format of the keys of [config-findings, health-findings]"},
+				{AccountID: "11111111111", Section: "health-findings", Key: "regex:AWS_(EC2", Kind: LintIssueInvalidKey, Message: "the key is not a valid regular expression"},
+				{AccountID: "11111111111", Section: "health-findings", Key: "rds security notification", Kind: LintIssueInvalidKey, Message: "the key doesn't have the format of the keys of health-findings: ^AWS_[A-Z0-9_]+$"},
+				{AccountID: "11111111111", Section: "ta-finding", Kind: LintIssueUnknownSection, Message: "no report has comments section 'ta-finding'"},
+			},
+		},
+		{
+			name: "Return duplicate keys of an account",
+			content: `
+- accountid: "111111111111"
+  config-findings:
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** First"
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** Second"
+- accountid: ["222222222222", "111111111111"]
+  config-findings:
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** Third"
+- accountid: "*"
+  config-findings:
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** Every account"
+`,
+			expectedOutput: []LintIssue{
+				{AccountID: "111111111111", Section: "config-findings", Key: "IAM_PASSWORD_POLICY", Kind: LintIssueDuplicateKey, Message: "the key is already commented for the account, the first comment of the file wins"},
+				{AccountID: "222222222222,111111111111", Section: "config-findings", Key: "IAM_PASSWORD_POLICY", Kind: LintIssueDuplicateKey, Message: "the key is already commented for the account, the first comment of the file wins"},
+			},
+		},
+		{
+			name: "Return stale keys of the reported sections",
+			content: `
+- accountid: "111111111111"
+  config-findings:
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** Matches a finding"
+    - S3_BUCKET_LOGGING_ENABLED: "**EXCEPTION:** Matches no finding"
+    - "regex:^IAM_.*": "**EXCEPTION:** Matches a finding"
+  health-findings:
+    - AWS_EC2_OPERATIONAL_ISSUE: "**EXCEPTION:** Not reported"
+- accountid: "222222222222"
+  config-findings:
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** Closed account"
+- accountid: "*"
+  config-findings:
+    - IAM_*: "**EXCEPTION:** Matches a finding of an account"
+    - EC2_*: "**EXCEPTION:** Matches no finding"
+`,
+			findings: map[string]map[string][]string{
+				"111111111111": {findingTypeAWSConfig: {"IAM_PASSWORD_POLICY"}},
+			},
+			accounts: []string{"111111111111", "222222222222"},
+			sections: []string{findingTypeAWSConfig},
+			expectedOutput: []LintIssue{
+				{AccountID: "111111111111", Section: "config-findings", Key: "S3_BUCKET_LOGGING_ENABLED", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+				{AccountID: "222222222222", Section: "config-findings", Key: "IAM_PASSWORD_POLICY", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+				{AccountID: "*", Section: "config-findings", Key: "EC2_*", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+			},
+		},
+		{
+			name: "Return no stale keys of the accounts that were not collected",
+			content: `
+- accountid: "111111111111"
+  config-findings:
+    - S3_BUCKET_LOGGING_ENABLED: "**EXCEPTION:** Matches no finding"
+- accountid: "333333333333"
+  config-findings:
+    - IAM_PASSWORD_POLICY: "**EXCEPTION:** Not collected"
+- accountid: ["111111111111", "333333333333"]
+  config-findings:
+    - EC2_INSTANCE_NO_PUBLIC_IP: "**EXCEPTION:** Not collected for every account"
+- accountid: "*"
+  config-findings:
+    - EC2_*: "**EXCEPTION:** Matches no finding"
+`,
+			findings: map[string]map[string][]string{},
+			accounts: []string{"111111111111"},
+			sections: []string{findingTypeAWSConfig},
+			expectedOutput: []LintIssue{
+				{AccountID: "111111111111", Section: "config-findings", Key: "S3_BUCKET_LOGGING_ENABLED", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+				{AccountID: "*", Section: "config-findings", Key: "EC2_*", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+			},
+		},
+		{
+			name: "Return stale keys of the image tags the comments are not looked up with",
+			content: `
+- accountid: "111111111111"
+  ecr-findings:
+    - 111111111111.dkr.ecr.us-east-1.amazonaws.com/app:v1: "**EXCEPTION:** First tag"
+    - 111111111111.dkr.ecr.us-east-1.amazonaws.com/app:latest: "**EXCEPTION:** Second tag"
+    - ALL:latest: "**EXCEPTION:** Second tag"
+`,
+			findings: map[string]map[string][]string{
+				"111111111111": {findingTypeECRScan: {"111111111111.dkr.ecr.us-east-1.amazonaws.com/app:v1"}},
+			},
+			accounts: []string{"111111111111"},
+			sections: []string{findingTypeECRScan},
+			expectedOutput: []LintIssue{
+				{AccountID: "111111111111", Section: "ecr-findings", Key: "111111111111.dkr.ecr.us-east-1.amazonaws.com/app:latest", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+				{AccountID: "111111111111", Section: "ecr-findings", Key: "ALL:latest", Kind: LintIssueStaleKey, Message: "the key matches none of the findings of the reports"},
+			},
+		},
+		{
+			name:           "Return no issue",
+			content:        "",
+			expectedOutput: []LintIssue{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var findings *CommentKeys
+			if tc.findings != nil {
+				findings = NewCommentKeys()
+				for accountID, sections := range tc.findings {
+					for section, keys := range sections {
+						for _, key := range keys {
+							findings.record(accountID, section, key, false)
+						}
+					}
+				}
+				findings.AddCollectedAccounts(tc.accounts...)
+			}
+			output := LintComments([]byte(tc.content), findings, tc.sections)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestParseReportJSON(t *testing.T) {
+	testCases := []struct {
+		name             string
+		content          string
+		reportName       string
+		expectedSections []string
+		expectedKeys     map[string]map[string][]string
+		expectedError    error
+	}{
+		{
+			name: "Return composite report",
+			content: `{
+  "health": {"findings": [{"accountId": "111111111111", "eventTypeCode": "Ec2 Operational Issue", "comments": "NEW_FINDING"}]},
+  "ecrscan": {"findings": [{"accountId": "111111111111", "imageTag": "v1,latest", "repositoryName": "app/web", "region": "us-east-1", "comments": "NEW_FINDING"}]},
+  "reportTime": "01-01-2021 00:00:00Z"
+}`,
+			expectedSections: []string{findingTypeECRScan, findingTypeAWSHealth},
+			expectedKeys: map[string]map[string][]string{
+				"111111111111": {
+					findingTypeAWSHealth: {"AWS_EC2_OPERATIONAL_ISSUE"},
+					findingTypeECRScan:   {"111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web:v1"},
+				},
+			},
+		},
+		{
+			name:             "Return report of the given name",
+			content:          `{"findings": [{"accountId": "111111111111", "category": "SECURITY", "name": "IAM Use", "comments": "NEW_FINDING"}, {"accountId": "111111111111", "category": "SECURITY", "name": "MFA on Root Account", "comments": "**EXCEPTION:** Break glass"}]}`,
+			reportName:       "ta",
+			expectedSections: []string{findingTypeTrustedAdvisor},
+			expectedKeys: map[string]map[string][]string{
+				"111111111111": {findingTypeTrustedAdvisor: {"SECURITY-IAM_Use"}},
+			},
+		},
+		{
+			name:          "Return error for a report without name",
+			content:       `{"findings": []}`,
+			expectedError: errors.New("unknown report '' of the JSON report"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			report, err := ParseReportJSON([]byte(tc.content), tc.reportName)
+			assert.Equal(t, tc.expectedError, err)
+			if err != nil {
+				return
+			}
+			assert.Equal(t, tc.expectedSections, GetCommentsKeys(report))
+			keys := NewCommentKeys()
+			keys.Record(report)
+			assert.Equal(t, tc.expectedKeys, keys.keys)
+		})
+	}
+}
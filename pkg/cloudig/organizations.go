@@ -0,0 +1,48 @@
+package cloudig
+
+import (
+	"fmt"
+
+	awslocal "github.com/Optum/cloudig/pkg/aws"
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/kris-nova/logger"
+)
+
+// GetOrganizationRoleARNs discovers the active accounts of the AWS Organization using the parent session and returns the
+// ARN of the role 'roleName' in each of them. Accounts can be filtered by organizational units and by account tags
+func GetOrganizationRoleARNs(sess *session.Session, roleName string, parentIDs []string, tags map[string]string) ([]string, error) {
+	partition := endpoints.AwsPartitionID
+	if p, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), aws.StringValue(sess.Config.Region)); ok {
+		partition = p.ID()
+	}
+	return getOrganizationRoleARNs(awslocal.NewClient(sess), partition, roleName, parentIDs, tags)
+}
+
+func getOrganizationRoleARNs(client awslocal.APIs, partition string, roleName string, parentIDs []string, tags map[string]string) ([]string, error) {
+	if roleName == "" {
+		return nil, fmt.Errorf("role name to assume in the organization accounts is required")
+	}
+	logger.Info("finding active accounts in the organization")
+	if len(parentIDs) > 0 {
+		logger.Debug("limiting organization accounts to the organizational units: %v", parentIDs)
+	}
+	if len(tags) > 0 {
+		logger.Debug("limiting organization accounts to the tags: %v", tags)
+	}
+	accountIDs, err := client.GetActiveAccounts(parentIDs, tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(accountIDs) == 0 {
+		return nil, fmt.Errorf("no active accounts found in the organization")
+	}
+	logger.Info("found %d active accounts in the organization", len(accountIDs))
+
+	roleARNs := make([]string, 0, len(accountIDs))
+	for _, accountID := range accountIDs {
+		roleARNs = append(roleARNs, fmt.Sprintf("arn:%s:iam::%s:role/%s", partition, accountID, roleName))
+	}
+	return roleARNs, nil
+}
@@ -0,0 +1,78 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/Optum/cloudig/pkg/mocks"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetOrganizationRoleARNs(t *testing.T) {
+	testCases := []struct {
+		name                        string
+		partition                   string
+		roleName                    string
+		parentIDs                   []string
+		tags                        map[string]string
+		mockGetActiveAccountsOutput []string
+		mockGetActiveAccountsError  error
+		skipGetActiveAccounts       bool
+		expectedOutput              []string
+		expectedError               error
+	}{
+		{
+			name:                        "Return role ARNs for every active account",
+			partition:                   "aws",
+			roleName:                    "cloudig",
+			mockGetActiveAccountsOutput: []string{"111111111111", "222222222222"},
+			expectedOutput:              []string{"arn:aws:iam::111111111111:role/cloudig", "arn:aws:iam::222222222222:role/cloudig"},
+		},
+		{
+			name:                        "Return role ARNs in the partition of the parent session",
+			partition:                   "aws-us-gov",
+			roleName:                    "audit/cloudig",
+			parentIDs:                   []string{"ou-1"},
+			tags:                        map[string]string{"env": "prod"},
+			mockGetActiveAccountsOutput: []string{"111111111111"},
+			expectedOutput:              []string{"arn:aws-us-gov:iam::111111111111:role/audit/cloudig"},
+		},
+		{
+			name:                        "Return error when no active accounts found",
+			partition:                   "aws",
+			roleName:                    "cloudig",
+			mockGetActiveAccountsOutput: []string{},
+			expectedError:               errors.New("no active accounts found in the organization"),
+		},
+		{
+			name:                       "Return error from Organizations",
+			partition:                  "aws",
+			roleName:                   "cloudig",
+			mockGetActiveAccountsError: errors.New("AWSOrganizationsNotInUseException"),
+			expectedError:              errors.New("AWSOrganizationsNotInUseException"),
+		},
+		{
+			name:                  "Return error when role name is missing",
+			partition:             "aws",
+			skipGetActiveAccounts: true,
+			expectedError:         errors.New("role name to assume in the organization accounts is required"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mockCtrl := gomock.NewController(t)
+			defer mockCtrl.Finish()
+
+			mockAPIs := mocks.NewMockAPIs(mockCtrl)
+			if !tc.skipGetActiveAccounts {
+				mockAPIs.EXPECT().GetActiveAccounts(tc.parentIDs, tc.tags).Return(tc.mockGetActiveAccountsOutput, tc.mockGetActiveAccountsError)
+			}
+
+			output, err := getOrganizationRoleARNs(mockAPIs, tc.partition, tc.roleName, tc.parentIDs, tc.tags)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
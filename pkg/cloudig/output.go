@@ -1,12 +1,15 @@
 package cloudig
 
 import (
+	"encoding/csv"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go/service/ecr"
 	"github.com/kris-nova/logger"
 	"github.com/olekukonko/tablewriter"
 )
@@ -16,8 +19,29 @@ const (
 	tableTypeMD     string = "mdtable"
 )
 
+// Output types supported by RenderReport
+const (
+	OutputTypeJSON    string = "json"
+	OutputTypeTable   string = tableTypeNormal
+	OutputTypeMDTable string = tableTypeMD
+	OutputTypeCSV     string = "csv"
+	OutputTypeTSV     string = "tsv"
+	OutputTypeHTML    string = "html"
+	OutputTypeSARIF   string = "sarif"
+	OutputTypeJUnit   string = "junit"
+)
+
+// ecrSeverities are the severity columns of the ECR scan CSV output, in a fixed order so that the header doesn't depend
+// on the severities found
+var ecrSeverities = []string{ecr.FindingSeverityCritical, ecr.FindingSeverityHigh, ecr.FindingSeverityMedium, ecr.FindingSeverityLow, ecr.FindingSeverityInformational, ecr.FindingSeverityUndefined}
+
 type jsonOutputHelper struct {
-	ReportTime string `json:"reportTime"`
+	ReportTime string        `json:"reportTime"`
+	Errors     []ReportError `json:"errors"`
+}
+
+func (helper *jsonOutputHelper) setErrors(reportErrors []ReportError) {
+	helper.Errors = reportErrors
 }
 
 func (helper *jsonOutputHelper) toJSON(report *Report) string {
@@ -32,15 +56,21 @@ func (helper *jsonOutputHelper) toJSON(report *Report) string {
 
 func (report *TrustedAdvisorReport) toTable(tableType string) string {
 	report.ReportTime = getCurrentTimestamp()
-	table, tableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "Name", "Flagged Resources", "Comments"})
+	showOwner := false
+	for _, finding := range report.Findings {
+		showOwner = showOwner || finding.Owner != "" || finding.Ticket != ""
+	}
+	table, tableString := getTableWriterWithHeaders(tableType, withOwnerColumns(showOwner, "Owner", "Ticket", []string{"Account ID", "Name", "Flagged Resources", "Comments"}))
 	// build table rows
 	for _, finding := range report.Findings {
 		nameCol := finding.Category + "\n" + finding.Name
 		flaggedResourcesCol := "Flagged Count: " + strconv.Itoa(len(finding.FlaggedResources)) + "\n" + strings.Join(finding.FlaggedResources, "\n")
-		table.Append([]string{finding.AccountID, nameCol, flaggedResourcesCol, finding.Comments})
+		if len(finding.SuppressedResources) != 0 {
+			flaggedResourcesCol += "\nSuppressed Count: " + strconv.Itoa(len(finding.SuppressedResources))
+		}
+		table.Append(withOwnerColumns(showOwner, finding.Owner, finding.Ticket, []string{finding.AccountID, nameCol, flaggedResourcesCol, finding.Comments}))
 	}
 
-	logger.Always("report Time: %s", report.ReportTime)
 	table.Render()
 
 	return tableString.String()
@@ -49,17 +79,21 @@ func (report *TrustedAdvisorReport) toTable(tableType string) string {
 func (report *ConfigReport) toTable(tableType string) string {
 	report.ReportTime = getCurrentTimestamp()
 
-	table, tableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "Name", "Flagged Resources", "Comments"})
+	showRegion, showOwner := false, false
+	for _, finding := range report.Findings {
+		showRegion = showRegion || finding.Region != ""
+		showOwner = showOwner || finding.Owner != "" || finding.Ticket != ""
+	}
+	table, tableString := getTableWriterWithHeaders(tableType, withOwnerColumns(showOwner, "Owner", "Ticket", withRegionColumn(showRegion, "Region", []string{"Account ID", "Name", "Flagged Resources", "Comments"})))
 	// build table rows
 	for _, finding := range report.Findings {
-		var flaggedResourcesCol string
-		for resourceType, flaggedResources := range finding.FlaggedResources {
-			flaggedResourcesCol = "Resource Type: " + resourceType + "\n" + strings.Join(flaggedResources, "\n")
+		flaggedResourcesCol := strings.Join(getConfigFlaggedResources(finding), "\n")
+		if len(finding.SuppressedResources) != 0 {
+			flaggedResourcesCol += "\nSuppressed Count: " + strconv.Itoa(len(finding.SuppressedResources))
 		}
-		table.Append([]string{finding.AccountID, finding.RuleName, flaggedResourcesCol, finding.Comments})
+		table.Append(withOwnerColumns(showOwner, finding.Owner, finding.Ticket, withRegionColumn(showRegion, finding.Region, []string{finding.AccountID, finding.RuleName, flaggedResourcesCol, finding.Comments})))
 	}
 
-	logger.Always("report Time: %s", report.ReportTime)
 	table.Render()
 
 	return tableString.String()
@@ -67,25 +101,41 @@ func (report *ConfigReport) toTable(tableType string) string {
 
 func (reports *InspectorReports) toTable(tableType string) string {
 	reports.ReportTime = getCurrentTimestamp()
-	findingsTable, findingsTableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "Template Name", "Rule Packages", "High", "Medium", "Low", "Informational", "Comments"})
+	showRegion, showOwner := false, false
+	for _, report := range reports.Reports {
+		showRegion = showRegion || report.Region != ""
+		for _, finding := range report.Findings {
+			showOwner = showOwner || finding.Owner != "" || finding.Ticket != ""
+		}
+	}
+	findingsTable, findingsTableString := getTableWriterWithHeaders(tableType, withOwnerColumns(showOwner, "Owner", "Ticket", withRegionColumn(showRegion, "Region", []string{"Account ID", "Template Name", "Rule Packages", "High", "Medium", "Low", "Informational", "Comments"})))
 
-	amiTable, amiTableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "AMI", "Age"})
+	amiTable, amiTableString := getTableWriterWithHeaders(tableType, withRegionColumn(showRegion, "Region", []string{"Account ID", "AMI", "Age"}))
 	amiTable.SetAutoMergeCells(true)
 
 	// build tables
 	for _, report := range reports.Reports {
 		for _, finding := range report.Findings {
-			findingsTable.Append([]string{report.AccountID, report.TemplateName, finding.RulePackageName, finding.High, finding.Medium, finding.Low, finding.Informational, finding.Comments})
+			findingsTable.Append(withOwnerColumns(showOwner, finding.Owner, finding.Ticket, withRegionColumn(showRegion, report.Region, []string{report.AccountID, report.TemplateName, finding.RulePackageName, finding.High, finding.Medium, finding.Low, finding.Informational, finding.Comments})))
 		}
 	}
 
+	// an assessment split by --sort-by or --group-by lists its AMIs once
+	amis := make(map[string]bool)
 	for _, report := range reports.Reports {
-		for ami, age := range report.AMI {
-			amiTable.Append([]string{report.AccountID, ami, strconv.Itoa(age) + " days"})
+		names := make([]string, 0, len(report.AMI))
+		for ami := range report.AMI {
+			names = append(names, ami)
+		}
+		sort.Strings(names)
+		for _, ami := range names {
+			if key := report.AccountID + "/" + report.Region + "/" + report.TemplateName + "/" + ami; !amis[key] {
+				amis[key] = true
+				amiTable.Append(withRegionColumn(showRegion, report.Region, []string{report.AccountID, ami, strconv.Itoa(report.AMI[ami]) + " days"}))
+			}
 		}
 	}
 
-	logger.Always("report Time: %s", reports.ReportTime)
 	findingsTable.Render()
 	amiTable.Render()
 
@@ -95,13 +145,16 @@ func (reports *InspectorReports) toTable(tableType string) string {
 func (report *HealthReport) toTable(tableType string) string {
 	report.ReportTime = getCurrentTimestamp()
 
-	table, tableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "Event Type Code", "Region", "Status Code", "Event Description", "Affected Resources", "Comments"})
+	showOwner := false
+	for _, finding := range report.Findings {
+		showOwner = showOwner || finding.Owner != "" || finding.Ticket != ""
+	}
+	table, tableString := getTableWriterWithHeaders(tableType, withOwnerColumns(showOwner, "Owner", "Ticket", []string{"Account ID", "Event Type Code", "Region", "Status Code", "Event Description", "Affected Resources", "Comments"}))
 	// build table rows
 	for _, finding := range report.Findings {
-		table.Append([]string{finding.AccountID, finding.EventTypeCode, finding.Region, finding.StatusCode, finding.EventDescription, strings.Join(finding.AffectedEntities, ", "), finding.Comments})
+		table.Append(withOwnerColumns(showOwner, finding.Owner, finding.Ticket, []string{finding.AccountID, finding.EventTypeCode, finding.Region, finding.StatusCode, finding.EventDescription, strings.Join(finding.AffectedEntities, ", "), finding.Comments}))
 	}
 
-	logger.Always("report Time: %s", report.ReportTime)
 	table.Render()
 
 	return tableString.String()
@@ -110,7 +163,11 @@ func (report *HealthReport) toTable(tableType string) string {
 func (report *ImageScanReports) toTable(tableType string) string {
 	report.ReportTime = getCurrentTimestamp()
 
-	table, tableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "Region", "Repository Name", "Tag", "Vulnerabilities(count)", "Comments"})
+	showOwner := false
+	for _, finding := range report.Findings {
+		showOwner = showOwner || finding.Owner != "" || finding.Ticket != ""
+	}
+	table, tableString := getTableWriterWithHeaders(tableType, withOwnerColumns(showOwner, "Owner", "Ticket", []string{"Account ID", "Region", "Repository Name", "Tag", "Vulnerabilities(count)", "Comments"}))
 	table.SetHeaderAlignment(tablewriter.ALIGN_LEFT)
 	table.SetAlignment(tablewriter.ALIGN_LEFT)
 	// build table rows
@@ -119,25 +176,28 @@ func (report *ImageScanReports) toTable(tableType string) string {
 	previousRegion := ""
 	for _, finding := range report.Findings {
 		var severityCount string
-		for severity, count := range finding.ImageFindingsCount {
-			severityCount = severityCount + fmt.Sprintf("%-15v %d\n", severity+":", count)
+		for _, severity := range getImageSeverities(finding) {
+			severityCount = severityCount + fmt.Sprintf("%-15v %d\n", severity+":", finding.ImageFindingsCount[severity])
 		}
 		severityCount = strings.Trim(severityCount, "\n")
-		if previousAccountID != finding.AccountID && previousRepo != finding.RepositoryName && previousRegion != finding.Region {
-			table.Append([]string{finding.AccountID, finding.Region, finding.RepositoryName, finding.ImageTag, severityCount, finding.Comments})
-		} else if previousAccountID == finding.AccountID && previousRegion != finding.Region && previousRepo != finding.RepositoryName {
-			table.Append([]string{"", finding.Region, finding.RepositoryName, finding.ImageTag, severityCount, finding.Comments})
-		} else if previousAccountID == finding.AccountID && previousRegion == finding.Region && previousRepo != finding.RepositoryName {
-			table.Append([]string{"", "", finding.RepositoryName, finding.ImageTag, severityCount, finding.Comments})
-		} else {
-			table.Append([]string{"", "", "", finding.ImageTag, severityCount, finding.Comments})
+		// a column is left blank when it and every column on its left repeat the previous row, so that rows re-sorted
+		// by --sort-by still tell their account and region
+		accountCol, regionCol, repoCol := finding.AccountID, finding.Region, finding.RepositoryName
+		if accountCol == previousAccountID {
+			accountCol = ""
+			if regionCol == previousRegion {
+				regionCol = ""
+				if repoCol == previousRepo {
+					repoCol = ""
+				}
+			}
 		}
+		table.Append(withOwnerColumns(showOwner, finding.Owner, finding.Ticket, []string{accountCol, regionCol, repoCol, finding.ImageTag, severityCount, finding.Comments}))
 		previousAccountID = finding.AccountID
 		previousRepo = finding.RepositoryName
 		previousRegion = finding.Region
 	}
 
-	logger.Always("report Time: %s", report.ReportTime)
 	table.Render()
 
 	return tableString.String()
@@ -146,7 +206,12 @@ func (report *ImageScanReports) toTable(tableType string) string {
 func (report *ReflectReport) toTable(tableType string) string {
 	report.ReportTime = getCurrentTimestamp()
 
-	table, tableString := getTableWriterWithHeaders(tableType, []string{"Account ID", "IAM Identity", "Access Details", "Actual Permissions", "Comments"})
+	showRegion, showOwner := false, false
+	for _, finding := range report.Findings {
+		showRegion = showRegion || finding.Region != ""
+		showOwner = showOwner || finding.Owner != "" || finding.Ticket != ""
+	}
+	table, tableString := getTableWriterWithHeaders(tableType, withOwnerColumns(showOwner, "Owner", "Ticket", withRegionColumn(showRegion, "Region", []string{"Account ID", "IAM Identity", "Access Details", "Actual Permissions", "Comments"})))
 	// build table rows
 	for _, finding := range report.Findings {
 		details := make([]string, 0)
@@ -155,15 +220,264 @@ func (report *ReflectReport) toTable(tableType string) string {
 		}
 		accDetCol := strings.Join(details, "\n")
 		perSetCol := strings.Join(finding.PermissionSet, "\n")
-		table.Append([]string{finding.AccountID, finding.Identity, accDetCol, perSetCol, finding.Comments})
+		table.Append(withOwnerColumns(showOwner, finding.Owner, finding.Ticket, withRegionColumn(showRegion, finding.Region, []string{finding.AccountID, finding.Identity, accDetCol, perSetCol, finding.Comments})))
 	}
 
-	logger.Always("report Time: %s", report.ReportTime)
 	table.Render()
 
 	return tableString.String()
 }
 
+// toJSON outputs every report of the composite as a section named after the report
+func (report *CompositeReport) toJSON(r *Report) string {
+	sections := map[string]interface{}{
+		"reportTime": getCurrentTimestamp(),
+	}
+	for _, child := range report.Reports {
+		sections[getReportName(child)] = json.RawMessage(child.toJSON(&child))
+	}
+	content, err := json.MarshalIndent(sections, "", "  ")
+
+	if err != nil {
+		logger.Critical("unable to marshal the output into JSON: %v", err)
+	}
+	return string(content)
+}
+
+// toTable outputs every report of the composite as a table under its own heading
+func (report *CompositeReport) toTable(tableType string) string {
+	var tables strings.Builder
+	for _, child := range report.Reports {
+		tables.WriteString(getTableTitle(tableType, getReportTitle(child), 2))
+		tables.WriteString(renderTable(child, tableType) + "\n")
+	}
+	return tables.String()
+}
+
+// renderTable renders a report as a table with the Table renderer of its report type, every report of a composite
+// under its own heading
+func renderTable(report Report, tableType string) string {
+	if composite, ok := report.(*CompositeReport); ok {
+		return composite.toTable(tableType)
+	}
+	reportType, ok := getReportTypeOf(report)
+	if !ok || reportType.Table == nil {
+		return ""
+	}
+	return reportType.Table(report, tableType)
+}
+
+// errorsToTable outputs the accounts that couldn't be reported as a footer table, nothing when there is no error
+func errorsToTable(tableType string, reportErrors []ReportError) string {
+	if len(reportErrors) == 0 {
+		return ""
+	}
+	table, tableString := getTableWriterWithHeaders(tableType, []string{"Account", "Region", "Report", "Stage", "Code", "Message"})
+	for _, e := range reportErrors {
+		table.Append([]string{e.Account, e.Region, e.Report, e.Stage, e.Code, e.Message})
+	}
+	table.Render()
+
+	title := "Errors"
+	if tableType == tableTypeMD {
+		return "\n## " + title + "\n\n" + tableString.String()
+	}
+	return "\n" + title + "\n" + strings.Repeat("=", len(title)) + "\n" + tableString.String()
+}
+
+func (report *TrustedAdvisorReport) toRecords() [][]string {
+	records := [][]string{{"Account ID", "Category", "Name", "Status", "Flagged Resource", "Comments", "Owner", "Ticket"}}
+	for _, finding := range report.Findings {
+		for _, resource := range resourcesOrEmpty(finding.FlaggedResources) {
+			records = append(records, []string{finding.AccountID, finding.Category, finding.Name, finding.Status, resource, finding.Comments, finding.Owner, finding.Ticket})
+		}
+	}
+	return records
+}
+
+func (report *ConfigReport) toRecords() [][]string {
+	records := [][]string{{"Account ID", "Region", "Name", "Status", "Resource Type", "Flagged Resource", "Comments", "Owner", "Ticket"}}
+	for _, finding := range report.Findings {
+		resourceTypes := make([]string, 0, len(finding.FlaggedResources))
+		for resourceType := range finding.FlaggedResources {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+		sort.Strings(resourceTypes)
+		if len(resourceTypes) == 0 {
+			resourceTypes = []string{""}
+		}
+		for _, resourceType := range resourceTypes {
+			for _, resource := range resourcesOrEmpty(finding.FlaggedResources[resourceType]) {
+				records = append(records, []string{finding.AccountID, finding.Region, finding.RuleName, finding.Status, resourceType, resource, finding.Comments, finding.Owner, finding.Ticket})
+			}
+		}
+	}
+	return records
+}
+
+func (reports *InspectorReports) toRecords() [][]string {
+	records := [][]string{{"Account ID", "Region", "Template Name", "Rule Package", "High", "Medium", "Low", "Informational", "Comments", "Owner", "Ticket"}}
+	for _, report := range reports.Reports {
+		for _, finding := range report.Findings {
+			records = append(records, []string{report.AccountID, report.Region, report.TemplateName, finding.RulePackageName, finding.High, finding.Medium, finding.Low, finding.Informational, finding.Comments, finding.Owner, finding.Ticket})
+		}
+	}
+	return records
+}
+
+func (report *HealthReport) toRecords() [][]string {
+	records := [][]string{{"Account ID", "Region", "Event Type Code", "Status Code", "Event Description", "Affected Resource", "Comments", "Owner", "Ticket"}}
+	for _, finding := range report.Findings {
+		for _, resource := range resourcesOrEmpty(finding.AffectedEntities) {
+			records = append(records, []string{finding.AccountID, finding.Region, finding.EventTypeCode, finding.StatusCode, finding.EventDescription, resource, finding.Comments, finding.Owner, finding.Ticket})
+		}
+	}
+	return records
+}
+
+func (report *ImageScanReports) toRecords() [][]string {
+	header := []string{"Account ID", "Region", "Repository Name", "Tag"}
+	for _, severity := range ecrSeverities {
+		header = append(header, strings.Title(strings.ToLower(severity)))
+	}
+	records := [][]string{append(header, "Comments", "Owner", "Ticket")}
+	for _, finding := range report.Findings {
+		row := []string{finding.AccountID, finding.Region, finding.RepositoryName, finding.ImageTag}
+		for _, severity := range ecrSeverities {
+			row = append(row, strconv.FormatInt(finding.ImageFindingsCount[severity], 10))
+		}
+		records = append(records, append(row, finding.Comments, finding.Owner, finding.Ticket))
+	}
+	return records
+}
+
+func (report *ReflectReport) toRecords() [][]string {
+	records := [][]string{{"Account ID", "Region", "IAM Identity", "IAM Action", "Usage Count", "Actual Permissions", "Comments", "Owner", "Ticket"}}
+	for _, finding := range report.Findings {
+		perSetCol := strings.Join(finding.PermissionSet, ",")
+		if len(finding.AccessDetails) == 0 {
+			records = append(records, []string{finding.AccountID, finding.Region, finding.Identity, "", "", perSetCol, finding.Comments, finding.Owner, finding.Ticket})
+		}
+		for _, ad := range finding.AccessDetails {
+			records = append(records, []string{finding.AccountID, finding.Region, finding.Identity, ad.Event, strconv.Itoa(ad.Count), perSetCol, finding.Comments, finding.Owner, finding.Ticket})
+		}
+	}
+	return records
+}
+
+// toRecords outputs the reports of the composite as one table, with the report name in the first column and the union
+// of the columns of the reports in the order of the reports
+func (report *CompositeReport) toRecords() [][]string {
+	header := []string{"Report"}
+	columns := map[string]int{"Report": 0}
+	children := make([][][]string, 0, len(report.Reports))
+	for _, child := range report.Reports {
+		records := child.toRecords()
+		for _, column := range records[0] {
+			if _, ok := columns[column]; !ok {
+				columns[column] = len(header)
+				header = append(header, column)
+			}
+		}
+		children = append(children, records)
+	}
+	records := [][]string{header}
+	for i, child := range report.Reports {
+		name := getReportName(child)
+		for _, childRow := range children[i][1:] {
+			row := make([]string, len(header))
+			row[0] = name
+			for j, value := range childRow {
+				row[columns[children[i][0][j]]] = value
+			}
+			records = append(records, row)
+		}
+	}
+	return records
+}
+
+// resourcesOrEmpty returns the resources of a finding, a single empty resource when there is none so that the finding
+// still has a row
+func resourcesOrEmpty(resources []string) []string {
+	if len(resources) == 0 {
+		return []string{""}
+	}
+	return resources
+}
+
+// recordsToCSV writes the records of a report as CSV with the given field delimiter
+func recordsToCSV(records [][]string, comma rune) string {
+	var csvString strings.Builder
+	w := csv.NewWriter(&csvString)
+	w.Comma = comma
+	if err := w.WriteAll(records); err != nil {
+		logger.Critical("unable to write the output as CSV: %v", err)
+	}
+	return csvString.String()
+}
+
+// CheckOutputType returns an error when the report can't be rendered with the output type: SARIF is only supported by
+// the ECR scan and Inspector reports, and by a composite of at least one of them, and the template of a template output
+// has to parse
+func CheckOutputType(report Report, outputType string) error {
+	switch {
+	case outputType == OutputTypeSARIF && !supportsSARIF(report):
+		return fmt.Errorf("%s output is only supported by the ecrscan and inspector reports", OutputTypeSARIF)
+	case strings.HasPrefix(outputType, OutputTypeTemplatePrefix):
+		_, err := loadOutputTemplate(outputType)
+		return err
+	}
+	return nil
+}
+
+// getConfigFlaggedResources returns the flagged resources of a finding under the heading of their resource type, sorted
+// by resource type
+func getConfigFlaggedResources(finding ConfigFinding) []string {
+	resourceTypes := make([]string, 0, len(finding.FlaggedResources))
+	for resourceType := range finding.FlaggedResources {
+		resourceTypes = append(resourceTypes, resourceType)
+	}
+	sort.Strings(resourceTypes)
+	flaggedResources := make([]string, 0)
+	for _, resourceType := range resourceTypes {
+		flaggedResources = append(append(flaggedResources, "Resource Type: "+resourceType), finding.FlaggedResources[resourceType]...)
+	}
+	return flaggedResources
+}
+
+// getImageSeverities returns the severities counted for an image from the most severe, unknown severities last
+func getImageSeverities(finding ImageScanFindings) []string {
+	severities := make([]string, 0, len(finding.ImageFindingsCount))
+	for severity := range finding.ImageFindingsCount {
+		severities = append(severities, severity)
+	}
+	sort.Slice(severities, func(i, j int) bool {
+		ri, rj := getSeverityRank(severities[i]), getSeverityRank(severities[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return severities[i] < severities[j]
+	})
+	return severities
+}
+
+// withRegionColumn inserts the region column right after the account ID column when the report has regional findings
+func withRegionColumn(showRegion bool, region string, row []string) []string {
+	if !showRegion {
+		return row
+	}
+	return append([]string{row[0], region}, row[1:]...)
+}
+
+// withOwnerColumns appends the owner and ticket columns after the comments column when the report has comments with an
+// owner or a ticket
+func withOwnerColumns(showOwner bool, owner string, ticket string, row []string) []string {
+	if !showOwner {
+		return row
+	}
+	return append(row, owner, ticket)
+}
+
 func getCurrentTimestamp() string {
 	return time.Now().Format(time.RFC822)
 }
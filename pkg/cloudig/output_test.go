@@ -18,7 +18,7 @@ func TestTrustedAdvisorTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedTable#1",
 			report: &TrustedAdvisorReport{
-				Findings: []trustedAdvisorFinding{
+				Findings: []TrustedAdvisorFinding{
 					{
 						AccountID:   "111111111111",
 						Category:    "COST_OPTIMIZING",
@@ -77,7 +77,7 @@ func TestTrustedAdvisorTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedMDTable#3",
 			report: &TrustedAdvisorReport{
-				Findings: []trustedAdvisorFinding{
+				Findings: []TrustedAdvisorFinding{
 					{
 						AccountID:   "111111111111",
 						Category:    "COST_OPTIMIZING",
@@ -127,6 +127,36 @@ func TestTrustedAdvisorTableOutput(t *testing.T) {
 			tableType: tableTypeMD,
 			expectedOutput: `| ACCOUNT ID | NAME | FLAGGED RESOURCES | COMMENTS |
 |------------|------|-------------------|----------|
+`,
+		},
+		{
+			name: "returnPopulatedMDTableWithOwner#5",
+			report: &TrustedAdvisorReport{
+				Findings: []TrustedAdvisorFinding{
+					{
+						AccountID:        "111111111111",
+						Category:         "SECURITY",
+						Name:             "IAM Use",
+						FlaggedResources: []string{"NA"},
+						Comments:         "**EXCEPTION:** Federation",
+						Owner:            "cloud-team",
+						Ticket:           "SEC-123",
+					}, {
+						AccountID:        "111111111111",
+						Category:         "SECURITY",
+						Name:             "MFA on Root Account",
+						FlaggedResources: []string{"NA"},
+						Comments:         "NEW_FINDING",
+					},
+				},
+			},
+			tableType: tableTypeMD,
+			expectedOutput: `|  ACCOUNT ID  |        NAME         | FLAGGED RESOURCES |         COMMENTS          |   OWNER    | TICKET  |
+|--------------|---------------------|-------------------|---------------------------|------------|---------|
+| 111111111111 | SECURITY            | Flagged Count: 1  | **EXCEPTION:** Federation | cloud-team | SEC-123 |
+|              | IAM Use             | NA                |                           |            |         |
+| 111111111111 | SECURITY MFA on     | Flagged Count: 1  | NEW_FINDING               |            |         |
+|              | Root Account        | NA                |                           |            |         |
 `,
 		},
 	}
@@ -148,7 +178,7 @@ func TestAWSConfigTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedTable#1",
 			report: &ConfigReport{
-				Findings: []configFinding{
+				Findings: []ConfigFinding{
 					{
 						AccountID:        "111111111111",
 						RuleName:         "ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK",
@@ -190,7 +220,7 @@ func TestAWSConfigTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedMDTable#3",
 			report: &ConfigReport{
-				Findings: []configFinding{
+				Findings: []ConfigFinding{
 					{
 						AccountID:        "111111111111",
 						RuleName:         "ALL_OPEN_INBOUND_PORTS_SECURITY_GROUP_CHECK",
@@ -223,6 +253,40 @@ func TestAWSConfigTableOutput(t *testing.T) {
 			tableType: tableTypeMD,
 			expectedOutput: `| ACCOUNT ID | NAME | FLAGGED RESOURCES | COMMENTS |
 |------------|------|-------------------|----------|
+`,
+		},
+		{
+			name: "returnPopulatedTableWithRegions#5",
+			report: &ConfigReport{
+				Findings: []ConfigFinding{
+					{
+						AccountID:        "111111111111",
+						Region:           "us-east-1",
+						RuleName:         "S3_BUCKET_LOGGING_ENABLED",
+						Status:           "NON_COMPLIANT",
+						FlaggedResources: map[string][]string{"AWS::S3::Bucket": {"dig-log-bucket-nonprod-222222222222"}},
+						Comments:         "NEW_FINDING",
+					},
+					{
+						AccountID:        "111111111111",
+						Region:           "us-west-2",
+						RuleName:         "S3_BUCKET_LOGGING_ENABLED",
+						Status:           "NON_COMPLIANT",
+						FlaggedResources: map[string][]string{"AWS::S3::Bucket": {"dig-log-bucket-nonprod-333333333333"}},
+						Comments:         "NEW_FINDING",
+					},
+				},
+			},
+			tableType: tableTypeNormal,
+			expectedOutput: `+--------------+-----------+---------------------------+-------------------------------------+-------------+
+|  ACCOUNT ID  |  REGION   |           NAME            |          FLAGGED RESOURCES          |  COMMENTS   |
++--------------+-----------+---------------------------+-------------------------------------+-------------+
+| 111111111111 | us-east-1 | S3_BUCKET_LOGGING_ENABLED | Resource Type: AWS::S3::Bucket      | NEW_FINDING |
+|              |           |                           | dig-log-bucket-nonprod-222222222222 |             |
++--------------+-----------+---------------------------+-------------------------------------+-------------+
+| 111111111111 | us-west-2 | S3_BUCKET_LOGGING_ENABLED | Resource Type: AWS::S3::Bucket      | NEW_FINDING |
+|              |           |                           | dig-log-bucket-nonprod-333333333333 |             |
++--------------+-----------+---------------------------+-------------------------------------+-------------+
 `,
 		},
 	}
@@ -245,11 +309,11 @@ func TestInspectorTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedTables#1",
 			reports: &InspectorReports{
-				Reports: []inspectorReport{
+				Reports: []InspectorReport{
 					{
 						AccountID:    "111111111111",
 						TemplateName: "k8s_weekly_scan",
-						Findings: []inspectorReportFinding{
+						Findings: []InspectorReportFinding{
 							{
 								RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0",
 								High:            "2581",
@@ -350,11 +414,11 @@ func TestInspectorTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedMDTables#3",
 			reports: &InspectorReports{
-				Reports: []inspectorReport{
+				Reports: []InspectorReport{
 					{
 						AccountID:    "111111111111",
 						TemplateName: "k8s_weekly_scan",
-						Findings: []inspectorReportFinding{
+						Findings: []InspectorReportFinding{
 							{
 								RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0",
 								High:            "2581",
@@ -395,7 +459,7 @@ func TestInspectorTableOutput(t *testing.T) {
 					{
 						AccountID:    "111111111111",
 						TemplateName: "test_scan",
-						Findings: []inspectorReportFinding{
+						Findings: []InspectorReportFinding{
 							{
 								RulePackageName: "CIS Operating System Security Configuration Benchmarks-1.0",
 								High:            "123",
@@ -485,7 +549,7 @@ func TestHealthTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedTable#1",
 			report: &HealthReport{
-				Findings: []healthReportFinding{
+				Findings: []HealthReportFinding{
 					{
 						AccountID:        "111111111111",
 						AffectedEntities: []string{"the-entity-0"},
@@ -554,7 +618,7 @@ func TestHealthTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedMDTable#3",
 			report: &HealthReport{
-				Findings: []healthReportFinding{
+				Findings: []HealthReportFinding{
 					{
 						AccountID:        "111111111111",
 						AffectedEntities: []string{"the-entity-0"},
@@ -634,11 +698,11 @@ func TestReflectReportTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedTable#1",
 			report: &ReflectReport{
-				Findings: []reflectFinding{
+				Findings: []ReflectFinding{
 					{
 						AccountID: "111111111111",
 						Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_BreakGlass",
-						AccessDetails: []accessDetails{
+						AccessDetails: []AccessDetails{
 							{"sts.amazonaws.com/AssumeRole", 15},
 							{"sts.amazonaws.com/AssumeRole/AccessDenied", 15},
 						},
@@ -648,7 +712,7 @@ func TestReflectReportTableOutput(t *testing.T) {
 					{
 						AccountID: "111111111111",
 						Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-						AccessDetails: []accessDetails{
+						AccessDetails: []AccessDetails{
 							{"iam.amazonaws.com/UpdateAssumeRolePolicy", 1},
 							{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 						},
@@ -658,7 +722,7 @@ func TestReflectReportTableOutput(t *testing.T) {
 					{
 						AccountID:     "111111111111",
 						Identity:      "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-						AccessDetails: []accessDetails{},
+						AccessDetails: []AccessDetails{},
 						PermissionSet: []string{"kms:Encrypt", "kms:DescribeKey"},
 						Comments:      "**EXCEPTION:** lets get this over with!",
 					},
@@ -682,11 +746,11 @@ func TestReflectReportTableOutput(t *testing.T) {
 		{
 			name: "returnPopulatedMDTable#2",
 			report: &ReflectReport{
-				Findings: []reflectFinding{
+				Findings: []ReflectFinding{
 					{
 						AccountID: "111111111111",
 						Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_BreakGlass",
-						AccessDetails: []accessDetails{
+						AccessDetails: []AccessDetails{
 							{"sts.amazonaws.com/AssumeRole", 15},
 							{"sts.amazonaws.com/AssumeRole/AccessDenied", 15},
 						},
@@ -696,7 +760,7 @@ func TestReflectReportTableOutput(t *testing.T) {
 					{
 						AccountID: "111111111111",
 						Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-						AccessDetails: []accessDetails{
+						AccessDetails: []AccessDetails{
 							{"iam.amazonaws.com/UpdateAssumeRolePolicy", 1},
 							{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 						},
@@ -706,7 +770,7 @@ func TestReflectReportTableOutput(t *testing.T) {
 					{
 						AccountID:     "111111111111",
 						Identity:      "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-						AccessDetails: []accessDetails{},
+						AccessDetails: []AccessDetails{},
 						PermissionSet: []string{"kms:Encrypt", "kms:DescribeKey"},
 						Comments:      "**EXCEPTION:** lets get this over with!",
 					},
@@ -731,3 +795,217 @@ func TestReflectReportTableOutput(t *testing.T) {
 		})
 	}
 }
+
+func TestCSVOutput(t *testing.T) {
+	testCases := []struct {
+		name           string
+		report         Report
+		outputType     string
+		expectedOutput string
+	}{
+		{
+			name: "returnTrustedAdvisorRowPerFlaggedResource#1",
+			report: &TrustedAdvisorReport{
+				Findings: []TrustedAdvisorFinding{
+					{AccountID: "111111111111", Category: "COST_OPTIMIZING", Name: "Low Utilization Amazon EC2 Instances", Status: "warning", FlaggedResources: []string{"i-0123456789abcdefg", "i-abcdefg0123456789"}, Comments: "NEW_FINDING"},
+					{AccountID: "111111111111", Category: "SECURITY", Name: "IAM Use", Status: "warning", Comments: "**EXCEPTION:** We use Federation, no IAM users", Owner: "team-a"},
+				},
+			},
+			outputType: OutputTypeCSV,
+			expectedOutput: `Account ID,Category,Name,Status,Flagged Resource,Comments,Owner,Ticket
+111111111111,COST_OPTIMIZING,Low Utilization Amazon EC2 Instances,warning,i-0123456789abcdefg,NEW_FINDING,,
+111111111111,COST_OPTIMIZING,Low Utilization Amazon EC2 Instances,warning,i-abcdefg0123456789,NEW_FINDING,,
+111111111111,SECURITY,IAM Use,warning,,"**EXCEPTION:** We use Federation, no IAM users",team-a,
+`,
+		},
+		{
+			name: "returnConfigRowPerResourceTypeAndResource#2",
+			report: &ConfigReport{
+				Findings: []ConfigFinding{
+					{AccountID: "111111111111", Region: "us-east-1", RuleName: "S3_BUCKET_LOGGING_ENABLED", Status: "NON_COMPLIANT", FlaggedResources: map[string][]string{"AWS::S3::Bucket": {"bucket-b", "bucket-a"}, "AWS::IAM::Role": {"role-a"}}, Comments: "NEW_FINDING"},
+				},
+			},
+			outputType: OutputTypeTSV,
+			expectedOutput: "Account ID\tRegion\tName\tStatus\tResource Type\tFlagged Resource\tComments\tOwner\tTicket\n" +
+				"111111111111\tus-east-1\tS3_BUCKET_LOGGING_ENABLED\tNON_COMPLIANT\tAWS::IAM::Role\trole-a\tNEW_FINDING\t\t\n" +
+				"111111111111\tus-east-1\tS3_BUCKET_LOGGING_ENABLED\tNON_COMPLIANT\tAWS::S3::Bucket\tbucket-b\tNEW_FINDING\t\t\n" +
+				"111111111111\tus-east-1\tS3_BUCKET_LOGGING_ENABLED\tNON_COMPLIANT\tAWS::S3::Bucket\tbucket-a\tNEW_FINDING\t\t\n",
+		},
+		{
+			name: "returnImageScanSeverityColumns#3",
+			report: &ImageScanReports{
+				Findings: []ImageScanFindings{
+					{AccountID: "111111111111", Region: "us-east-1", RepositoryName: "app/web", ImageTag: "v1,latest", ImageFindingsCount: map[string]int64{"HIGH": 2, "LOW": 5}, Comments: "NEW_FINDING"},
+				},
+			},
+			outputType: OutputTypeCSV,
+			expectedOutput: `Account ID,Region,Repository Name,Tag,Critical,High,Medium,Low,Informational,Undefined,Comments,Owner,Ticket
+111111111111,us-east-1,app/web,"v1,latest",0,2,0,5,0,0,NEW_FINDING,,
+`,
+		},
+		{
+			name: "returnReflectRowPerEvent#4",
+			report: &ReflectReport{
+				Findings: []ReflectFinding{
+					{AccountID: "111111111111", Identity: "arn:aws:iam::111111111111:role/Read", AccessDetails: []AccessDetails{{"sts.amazonaws.com/AssumeRole", 15}, {"kms.amazonaws.com/ListKeys", 1}}, PermissionSet: []string{"kms:ListKeys"}, Comments: "NEW_FINDING"},
+				},
+			},
+			outputType: OutputTypeCSV,
+			expectedOutput: `Account ID,Region,IAM Identity,IAM Action,Usage Count,Actual Permissions,Comments,Owner,Ticket
+111111111111,,arn:aws:iam::111111111111:role/Read,sts.amazonaws.com/AssumeRole,15,kms:ListKeys,NEW_FINDING,,
+111111111111,,arn:aws:iam::111111111111:role/Read,kms.amazonaws.com/ListKeys,1,kms:ListKeys,NEW_FINDING,,
+`,
+		},
+		{
+			name:       "returnHeaderOnlyForEmptyReport#5",
+			report:     &HealthReport{},
+			outputType: OutputTypeCSV,
+			expectedOutput: `Account ID,Region,Event Type Code,Status Code,Event Description,Affected Resource,Comments,Owner,Ticket
+`,
+		},
+		{
+			name: "returnCompositeUnionOfColumns#6",
+			report: &CompositeReport{Reports: []Report{
+				&HealthReport{Findings: []HealthReportFinding{
+					{AccountID: "111111111111", Region: "us-east-1", EventTypeCode: "AWS_EC2_OPERATIONAL_ISSUE", StatusCode: "open", AffectedEntities: []string{"i-0123456789abcdefg"}, Comments: "NEW_FINDING"},
+				}},
+				&InspectorReports{Reports: []InspectorReport{
+					{AccountID: "111111111111", Region: "us-east-1", TemplateName: "weekly", Findings: []InspectorReportFinding{{RulePackageName: "Common Vulnerabilities and Exposures-1.1", High: "1", Medium: "0", Low: "0", Informational: "0", Comments: "NEW_FINDING"}}},
+				}},
+			}},
+			outputType: OutputTypeCSV,
+			expectedOutput: `Report,Account ID,Region,Event Type Code,Status Code,Event Description,Affected Resource,Comments,Owner,Ticket,Template Name,Rule Package,High,Medium,Low,Informational
+health,111111111111,us-east-1,AWS_EC2_OPERATIONAL_ISSUE,open,,i-0123456789abcdefg,NEW_FINDING,,,,,,,,
+inspector,111111111111,us-east-1,,,,,NEW_FINDING,,,weekly,Common Vulnerabilities and Exposures-1.1,1,0,0,0
+`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := RenderReport(tc.report, tc.outputType, []ReportError{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestErrorsTableOutput(t *testing.T) {
+	reportErrors := []ReportError{
+		{
+			Account:   "arn:aws:iam::222222222222:role/cloudig",
+			AccountID: "222222222222",
+			Region:    "us-east-1",
+			Report:    "trustedadvisor",
+			Stage:     "assumeRole",
+			Code:      "AccessDenied",
+			Message:   "AccessDenied: not authorized",
+		},
+	}
+	testCases := []struct {
+		name           string
+		reportErrors   []ReportError
+		tableType      string
+		expectedOutput string
+	}{
+		{
+			name:         "returnPopulatedTable#1",
+			reportErrors: reportErrors,
+			tableType:    tableTypeNormal,
+			expectedOutput: `
+Errors
+======
++----------------------------------------+-----------+----------------+------------+--------------+------------------------------+
+|                ACCOUNT                 |  REGION   |     REPORT     |   STAGE    |     CODE     |           MESSAGE            |
++----------------------------------------+-----------+----------------+------------+--------------+------------------------------+
+| arn:aws:iam::222222222222:role/cloudig | us-east-1 | trustedadvisor | assumeRole | AccessDenied | AccessDenied: not authorized |
++----------------------------------------+-----------+----------------+------------+--------------+------------------------------+
+`,
+		},
+		{
+			name:         "returnPopulatedMDTable#2",
+			reportErrors: reportErrors,
+			tableType:    tableTypeMD,
+			expectedOutput: `
+## Errors
+
+|                ACCOUNT                 |  REGION   |     REPORT     |   STAGE    |     CODE     |           MESSAGE            |
+|----------------------------------------|-----------|----------------|------------|--------------|------------------------------|
+| arn:aws:iam::222222222222:role/cloudig | us-east-1 | trustedadvisor | assumeRole | AccessDenied | AccessDenied: not authorized |
+`,
+		},
+		{
+			name:           "returnNothingWithoutErrors#3",
+			reportErrors:   []ReportError{},
+			tableType:      tableTypeNormal,
+			expectedOutput: "",
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output := errorsToTable(tc.tableType, tc.reportErrors)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestRenderReport(t *testing.T) {
+	reportErrors := []ReportError{{Account: "parent", AccountID: "parent", Region: "us-east-1", Report: "awsconfig", Stage: "getReport", Message: "some error"}}
+
+	output, err := RenderReport(&ConfigReport{}, OutputTypeJSON, reportErrors)
+	assert.NoError(t, err)
+	assert.Contains(t, output, `"findings": null`)
+	assert.Contains(t, output, `"errors": [
+    {
+      "account": "parent",
+      "accountId": "parent",
+      "region": "us-east-1",
+      "report": "awsconfig",
+      "stage": "getReport",
+      "code": "",
+      "message": "some error"
+    }
+  ]`)
+
+	output, err = RenderReport(&ConfigReport{}, OutputTypeTable, reportErrors)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "| ACCOUNT ID | NAME | FLAGGED RESOURCES | COMMENTS |")
+	assert.Contains(t, output, "| parent  | us-east-1 | awsconfig | getReport |      | some error |")
+}
+
+func TestHTMLOutput(t *testing.T) {
+	report := &CompositeReport{Reports: []Report{
+		&TrustedAdvisorReport{Findings: []TrustedAdvisorFinding{
+			{AccountID: "222222222222", Category: "SECURITY", Name: "IAM Use", Status: "warning", FlaggedResources: []string{"<NA>"}, Comments: "**EXCEPTION:** We use `federation`, see [the wiki](https://wiki.example.com/iam)"},
+			{AccountID: "111111111111", Category: "SECURITY", Name: "MFA on Root Account", Status: "error", Comments: "NEW_FINDING"},
+		}},
+		&ImageScanReports{Findings: []ImageScanFindings{
+			{AccountID: "111111111111", Region: "us-east-1", RepositoryName: "app/web", ImageTag: "v1,latest", ImageFindingsCount: map[string]int64{"CRITICAL": 1, "LOW": 3}, Comments: "EXPIRED_EXCEPTION"},
+		}},
+	}}
+	reportErrors := []ReportError{{Account: "arn:aws:iam::333333333333:role/cloudig", Region: "us-east-1", Report: "trustedadvisor", Stage: "assumeRole", Code: "AccessDenied", Message: "AccessDenied: not authorized"}}
+
+	output, err := RenderReport(report, OutputTypeHTML, reportErrors)
+	assert.NoError(t, err)
+	assert.Contains(t, output, "<title>cloudig all report</title>")
+	// summary per account
+	assert.Contains(t, output, "<tr><th>Account ID</th><th>Trusted Advisor findings (new)</th><th>ECR Image Scan findings (new)</th></tr>")
+	assert.Contains(t, output, `<tr><td>111111111111</td><td>1 <span class="new-count">(1 new)</span></td><td>1 <span class="new-count">(1 new)</span></td></tr>`)
+	assert.Contains(t, output, "<tr><td>222222222222</td><td>1</td><td>0</td></tr>")
+	// a section per report without the empty owner columns
+	assert.Contains(t, output, `<details open id="trustedadvisor">`)
+	assert.Contains(t, output, "<tr><th>Account ID</th><th>Name</th><th>Status</th><th>Flagged Resources</th><th>Comments</th></tr>")
+	assert.Contains(t, output, "<td>Flagged Count: 1<br>&lt;NA&gt;</td>")
+	// rendered markdown
+	assert.Contains(t, output, `<td><strong>EXCEPTION:</strong> We use <code>federation</code>, see <a href="https://wiki.example.com/iam">the wiki</a></td>`)
+	assert.Contains(t, output, `<td class="new-finding">NEW_FINDING</td>`)
+	// color-coded severities
+	assert.Contains(t, output, `<td class="severity-critical">1</td><td>0</td><td>0</td><td class="severity-low">3</td><td>0</td><td>0</td>`)
+	assert.Contains(t, output, "<td>arn:aws:iam::333333333333:role/cloudig</td><td>us-east-1</td><td>trustedadvisor</td><td>assumeRole</td><td>AccessDenied</td><td>AccessDenied: not authorized</td>")
+	assert.NotContains(t, output, "<link")
+	assert.NotContains(t, output, "<script")
+
+	output, err = RenderReport(&HealthReport{}, OutputTypeHTML, []ReportError{})
+	assert.NoError(t, err)
+	assert.Contains(t, output, "<summary>Health (0)</summary>")
+	assert.NotContains(t, output, `id="errors"`)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
@@ -32,20 +33,25 @@ const (
 
 // ReflectReport is struct that contains a slice of Reflect findings
 type ReflectReport struct {
-	Findings []reflectFinding `json:"findings"`
+	Findings []ReflectFinding `json:"findings"`
 	Flags    ReflectFlags     `json:"-"` // hide in json output
 	jsonOutputHelper
 }
 
-type reflectFinding struct {
+// ReflectFinding is the usage of an IAM identity compared to its actual permissions
+type ReflectFinding struct {
 	AccountID     string          `json:"accountId"`
+	Region        string          `json:"region"`
 	Identity      string          `json:"IAMIdentity"`
-	AccessDetails []accessDetails `json:"accessDetails"`
+	AccessDetails []AccessDetails `json:"accessDetails"`
 	PermissionSet []string        `json:"permissionSet"`
 	Comments      string          `json:"comments"`
+	Owner         string          `json:"owner,omitempty"`
+	Ticket        string          `json:"ticket,omitempty"`
 }
 
-type accessDetails struct {
+// AccessDetails is the count of an event of an IAM identity
+type AccessDetails struct {
 	Event string `json:"IAMAction"`
 	Count int    `json:"UsageCount"`
 }
@@ -81,6 +87,86 @@ type runQueryResult struct {
 	err    error
 }
 
+func init() {
+	RegisterReportType(ReportType{
+		Name:        "reflectiam",
+		Title:       "Reflect IAM",
+		Command:     CommandReflect,
+		Use:         "iam",
+		Aliases:     []string{"i", "iamrole"},
+		Short:       "Reflect on IAM Role permissions",
+		CommentsKey: findingTypeReflectIAM,
+		// IAM identity ARN, ex: arn:aws:iam::111111111111:role/eks-worker
+		CommentsKeyFormat: regexp.MustCompile(`^arn:aws[a-z-]*:iam::[0-9]{12}:\S+$`),
+		Report:            &ReflectReport{},
+		CountFindings:     countReflectFindings,
+		Options: []ReportOption{
+			{Name: "identity", Shorthand: "i", Default: "", Usage: "One or more IAM Identities (users, groups, and roles) ARNs separated by a comma [,].Only role ARNs is supported today"},
+			{Name: "identity-tags", Shorthand: "t", Default: "", Usage: "Set of tags in form [key:value] separated by [,] to find the targeted IAM Identities. Only role ARN is supported today. Ignored when --identity is provided"},
+			// if -u or -e is not provided both will be true, if one of them is provided then the other one is false
+			{Name: "usage", Shorthand: "u", Default: false, Usage: "Reflect Identity usage data (default true, if --errors/-e is not explicitly provided)"},
+			{Name: "errors", Shorthand: "e", Default: false, Usage: "Reflect Identity error data (default true, if --usage/-u is not explicitly provided)"},
+			{Name: "caller-identity", Default: false, Usage: "Include caller identity with the report(default false)"},
+			{Name: "absolute-time", Default: "", Usage: "Specify both the start and end times for the time filter in the form 'startTime-endTime' 'mm/dd/yyyy-mm/dd/yyy' ex: '10/25/2020-10/31/2020'"},
+			{Name: "relative-time", Default: 1, Usage: "Specify a time filter relative to the current time in days. Default 1 day. Ignored when absolute-time is provided"},
+		},
+		Table: func(report Report, tableType string) string {
+			return report.(*ReflectReport).toTable(tableType)
+		},
+		New: func(options ReportOptions) (Report, error) {
+			identityARNs, identityTags := options.GetString("identity"), options.GetString("identity-tags")
+			includeUsage, includeErrors, includeCallerIdentity := options.GetBool("usage"), options.GetBool("errors"), options.GetBool("caller-identity")
+			absoluteTime, relativeTime := options.GetString("absolute-time"), options.GetInt("relative-time")
+			logger.Debug("all reflect command flags:\nidentityARNs: %s\nidentityTags: %s\nincludeUsage: %t\nincludeErrors: %t\nincludeCallerIdentity: %t\nabsoluteTime: %s\nrelativeTime: %d\n", identityARNs, identityTags, includeUsage, includeErrors, includeCallerIdentity, absoluteTime, relativeTime)
+			var roles []string
+			if identityARNs != "" {
+				roles = strings.Split(identityARNs, ",")
+			}
+
+			// if -u or -e is not provided both will be true, if one of them is provided then the other one is false
+			usage, errorReport := includeUsage, includeErrors
+			if !usage && !errorReport {
+				usage = true
+				errorReport = true
+			}
+
+			if err := validateAbsoluteTime(absoluteTime); err != nil {
+				return nil, err
+			}
+
+			return &ReflectReport{
+				Flags: NewReflectFlags(options.Region, roles, ParseTags(identityTags), usage, errorReport, includeCallerIdentity, absoluteTime, relativeTime),
+			}, nil
+		},
+	})
+}
+
+// validateAbsoluteTime validates the absolute time filter is in the form 'mm/dd/yyyy-mm/dd/yyy' with the start before the end
+func validateAbsoluteTime(absoluteTime string) error {
+	if absoluteTime == "" {
+		return nil
+	}
+	errorMessage := fmt.Errorf("--absolute-time is wrong. It should be in the form 'startTime-endTime' 'mm/dd/yyyy-mm/dd/yyy' ex: '10/25/2020-10/31/2020'")
+	dates := strings.Split(absoluteTime, "-")
+	if len(dates) != 2 {
+		return errorMessage
+	}
+	format := "01/02/2006" // mm/dd/yyyy format
+	// convert to time
+	startTime, err := time.Parse(format, dates[0])
+	if err != nil {
+		return errorMessage
+	}
+	endTime, err := time.Parse(format, dates[1])
+	if err != nil {
+		return errorMessage
+	}
+	if endTime.Before(startTime) {
+		return errorMessage
+	}
+	return nil
+}
+
 // GetReport retrives the reflect report for a given account
 func (report *ReflectReport) GetReport(client awslocal.APIs, comments []Comments) error {
 	start := time.Now()
@@ -103,14 +189,8 @@ func (report *ReflectReport) GetReport(client awslocal.APIs, comments []Comments
 	}
 
 	logger.Debug("retrieving the list of AWS regions")
-	regionList := make([]string, 0)
-	for _, p := range endpoints.DefaultPartitions() {
-		for region := range p.Regions() {
-			regionList = append(regionList, region)
-		}
-	}
 	// Consistent ordering avoids creating table due to metadata mismatch
-	sort.Strings(regionList)
+	regionList := getPartitionRegions(endpoints.DefaultPartitions()...)
 
 	logger.Info("constructing the Athena table metadata form the s3 prefix for account: %s", accountID)
 	// construct Athena table metadata from s3 location
@@ -180,17 +260,45 @@ func (report *ReflectReport) GetReport(client awslocal.APIs, comments []Comments
 	// loop through all findings to add comments and policy actions
 	for k, v := range findings {
 		findings[k].AccountID = accountID
+		findings[k].Region = flags.region
 		findings[k].PermissionSet = permissionForRoles[strings.Split(v.Identity, identityDelimiter)[0]]
-		findings[k].Comments = getComments(comments, accountID, findingTypeReflectIAM, v.Identity)
+		comment := getComments(comments, accountID, findingTypeReflectIAM, v.Identity)
+		findings[k].Comments, findings[k].Owner, findings[k].Ticket = comment.String(), comment.Owner, comment.Ticket
+	}
+	// the usage and error queries run concurrently, sort their findings so that the output doesn't change from run to run
+	sort.SliceStable(findings, func(i, j int) bool { return findings[i].Identity < findings[j].Identity })
+	for _, finding := range findings {
+		sort.SliceStable(finding.AccessDetails, func(i, j int) bool { return finding.AccessDetails[i].Event < finding.AccessDetails[j].Event })
 	}
 	report.Findings = append(report.Findings, findings...)
 	logger.Success("reflecting on account %s took %s", accountID, time.Since(start))
 	return nil
 }
 
-func populateFindings(client awslocal.APIs, tableName string, flags ReflectFlags) ([]reflectFinding, error) {
+func (report *ReflectReport) newReport(region string) Report {
+	flags := report.Flags
+	flags.region = region
+	return &ReflectReport{Flags: flags}
+}
+
+func (report *ReflectReport) mergeReport(r Report) {
+	report.Findings = append(report.Findings, r.(*ReflectReport).Findings...)
+}
+
+func (report *ReflectReport) recordCommentKeys(keys *CommentKeys) {
+	for _, finding := range report.Findings {
+		keys.record(finding.AccountID, findingTypeReflectIAM, finding.Identity, finding.Comments == commentNewFinding)
+	}
+}
+
+// CloudTrail events are queried for the region of the report
+func (report *ReflectReport) isRegional() bool {
+	return true
+}
+
+func populateFindings(client awslocal.APIs, tableName string, flags ReflectFlags) ([]ReflectFinding, error) {
 	var wg sync.WaitGroup
-	findings := make([]reflectFinding, 0)
+	findings := make([]ReflectFinding, 0)
 	output := make(chan runQueryResult, 2)
 
 	if flags.usageReport {
@@ -247,7 +355,7 @@ func populateFindings(client awslocal.APIs, tableName string, flags ReflectFlags
 }
 
 // create or update finding
-func updateFinding(flags ReflectFlags, findings *[]reflectFinding, identity string, eventD accessDetails) {
+func updateFinding(flags ReflectFlags, findings *[]ReflectFinding, identity string, eventD AccessDetails) {
 	// Filterting of the results come into play only when number of roles are > 1
 	// when no roles are provided, all results are returned
 	// when a single role is provided , query is designed to return result for single role
@@ -258,7 +366,7 @@ func updateFinding(flags ReflectFlags, findings *[]reflectFinding, identity stri
 	}
 
 	found := false
-	// add to accessDetails if Identity is already in the slice
+	// add to AccessDetails if Identity is already in the slice
 	for k, v := range *findings {
 		if v.Identity == identity {
 			for adk, adv := range v.AccessDetails {
@@ -269,7 +377,7 @@ func updateFinding(flags ReflectFlags, findings *[]reflectFinding, identity stri
 				}
 			}
 
-			(*findings)[k] = reflectFinding{
+			(*findings)[k] = ReflectFinding{
 				Identity:      identity,
 				AccessDetails: append(v.AccessDetails, eventD),
 			}
@@ -279,8 +387,8 @@ func updateFinding(flags ReflectFlags, findings *[]reflectFinding, identity stri
 	}
 	// add new finding if identity is not found in the slice
 	if !found {
-		ed := make([]accessDetails, 0)
-		*findings = append(*findings, reflectFinding{
+		ed := make([]AccessDetails, 0)
+		*findings = append(*findings, ReflectFinding{
 			Identity:      identity,
 			AccessDetails: append(ed, eventD),
 		})
@@ -288,8 +396,8 @@ func updateFinding(flags ReflectFlags, findings *[]reflectFinding, identity stri
 }
 
 // constructFinding is a helper function that transforms a row of the query output to finding based on row header(keys)
-func constructFinding(dataSlice, keys []string) (string, accessDetails) {
-	eventD := accessDetails{}
+func constructFinding(dataSlice, keys []string) (string, AccessDetails) {
+	eventD := AccessDetails{}
 	var identity string
 	var hasErrorCode, hasIdentity bool
 	indexMap := make(map[string]int)
@@ -113,7 +113,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 	tests := []struct {
 		name                                      string
 		args                                      args
-		initFindings                              []reflectFinding
+		initFindings                              []ReflectFinding
 		flags                                     ReflectFlags
 		mockedGetS3LogPrefixForCloudTrailResponse *string
 		mockedGetS3LogPrefixForCloudTrailError    error
@@ -128,7 +128,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 		mockedUsageReportRunQueryError            error
 		mockedErrorReportRunQueryError            error
 		GetNetIAMPermissionsForRolesResponse      map[string][]string
-		updatedFindings                           []reflectFinding
+		updatedFindings                           []ReflectFinding
 		wantErr                                   bool
 	}{
 		{
@@ -136,7 +136,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", []string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read"}, map[string]string{}, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: nil,
 			mockedGetS3LogPrefixForCloudTrailError:    errors.New("some error"),
@@ -151,7 +151,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{},
-			updatedFindings:                           []reflectFinding{},
+			updatedFindings:                           []ReflectFinding{},
 			wantErr:                                   true,
 		},
 		{
@@ -159,7 +159,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", []string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read"}, map[string]string{}, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: nil,
 			mockedGetS3LogPrefixForCloudTrailError:    nil,
@@ -174,7 +174,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{},
-			updatedFindings:                           []reflectFinding{},
+			updatedFindings:                           []ReflectFinding{},
 			wantErr:                                   true,
 		},
 		{
@@ -182,7 +182,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", []string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read"}, map[string]string{}, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: aws.String("s3://lp-cl-111111111111-us-east-1/source=aws/account=111111111111/region=us-east-1/env=prod/aggregation=cloudtrail/service=cloudtrail/AWSLogs/111111111111/CloudTrail"),
 			mockedGetS3LogPrefixForCloudTrailError:    nil,
@@ -197,7 +197,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{},
-			updatedFindings:                           []reflectFinding{},
+			updatedFindings:                           []ReflectFinding{},
 			wantErr:                                   true,
 		},
 		{
@@ -205,7 +205,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", []string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read"}, map[string]string{}, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: aws.String("s3://lp-cl-111111111111-us-east-1/source=aws/account=111111111111/region=us-east-1/env=prod/aggregation=cloudtrail/service=cloudtrail/AWSLogs/111111111111/CloudTrail"),
 			mockedGetS3LogPrefixForCloudTrailError:    nil,
@@ -220,7 +220,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{},
-			updatedFindings:                           []reflectFinding{},
+			updatedFindings:                           []ReflectFinding{},
 			wantErr:                                   true,
 		},
 		{
@@ -228,7 +228,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", []string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read"}, map[string]string{}, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: aws.String("s3://lp-cl-111111111111-us-east-1/source=aws/account=111111111111/region=us-east-1/env=prod/aggregation=cloudtrail/service=cloudtrail/AWSLogs/111111111111/CloudTrail"),
 			mockedGetS3LogPrefixForCloudTrailError:    nil,
@@ -243,11 +243,12 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					AccountID: "111111111111",
+					Region:    "us-east-1",
 					Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy", 1},
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 					},
@@ -262,7 +263,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", nil, map[string]string{"myapp": "awesome"}, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: aws.String("s3://lp-cl-111111111111-us-east-1/source=aws/account=111111111111/region=us-east-1/env=prod/aggregation=cloudtrail/service=cloudtrail/AWSLogs/111111111111/CloudTrail"),
 			mockedGetS3LogPrefixForCloudTrailError:    nil,
@@ -277,11 +278,12 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read": {"iam:UpdateAssumeRolePolicy", "s3:GetObject"}},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					AccountID: "111111111111",
+					Region:    "us-east-1",
 					Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy", 1},
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 					},
@@ -296,7 +298,7 @@ func TestReflectReport_GetReport(t *testing.T) {
 			args: args{
 				[]Comments{},
 			},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags:        NewReflectFlags("us-east-1", nil, nil, true, true, false, "", 1),
 			mockedGetS3LogPrefixForCloudTrailResponse: aws.String("s3://lp-cl-111111111111-us-east-1/source=aws/account=111111111111/region=us-east-1/env=prod/aggregation=cloudtrail/service=cloudtrail/AWSLogs/111111111111/CloudTrail"),
 			mockedGetS3LogPrefixForCloudTrailError:    nil,
@@ -311,11 +313,12 @@ func TestReflectReport_GetReport(t *testing.T) {
 			mockedUsageReportRunQueryError:            nil,
 			mockedErrorReportRunQueryError:            nil,
 			GetNetIAMPermissionsForRolesResponse:      map[string][]string{"arn:aws:iam::111111111111:role/AWS_111111111111_Read": {"iam:UpdateAssumeRolePolicy", "s3:GetObject"}},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					AccountID: "111111111111",
+					Region:    "us-east-1",
 					Identity:  "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy", 1},
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 					},
@@ -368,19 +371,19 @@ func TestReflectReport_populateFindings(t *testing.T) {
 	tests := []struct {
 		name                              string
 		args                              args
-		initFindings                      []reflectFinding
+		initFindings                      []ReflectFinding
 		flags                             ReflectFlags
 		mockedUsageReportRunQueryResponse *athena.ResultSet
 		mockedErrorReportRunQueryResponse *athena.ResultSet
 		mockedUsageReportRunQueryError    error
 		mockedErrorReportRunQueryError    error
-		updatedFindings                   []reflectFinding
+		updatedFindings                   []ReflectFinding
 		wantErr                           bool
 	}{
 		{
 			name:         "noData#1",
 			args:         args{"default.reflect_cloudTrail_test1"},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags: ReflectFlags{
 				usageReport: true,
 				errorReport: true,
@@ -389,13 +392,13 @@ func TestReflectReport_populateFindings(t *testing.T) {
 			mockedErrorReportRunQueryResponse: &athena.ResultSet{},
 			mockedUsageReportRunQueryError:    nil,
 			mockedErrorReportRunQueryError:    nil,
-			updatedFindings:                   []reflectFinding{},
+			updatedFindings:                   []ReflectFinding{},
 			wantErr:                           false,
 		},
 		{
 			name:         "runQueryError#2",
 			args:         args{"default.reflect_cloudTrail_test1"},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags: ReflectFlags{
 				usageReport: true,
 				errorReport: true,
@@ -404,13 +407,13 @@ func TestReflectReport_populateFindings(t *testing.T) {
 			mockedErrorReportRunQueryResponse: &athena.ResultSet{},
 			mockedUsageReportRunQueryError:    errors.New("some error"),
 			mockedErrorReportRunQueryError:    nil,
-			updatedFindings:                   []reflectFinding{},
+			updatedFindings:                   []ReflectFinding{},
 			wantErr:                           true,
 		},
 		{
 			name:         "dataFromBothQueries#3",
 			args:         args{"default.reflect_cloudTrail_test1"},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags: ReflectFlags{
 				usageReport: true,
 				errorReport: true,
@@ -533,24 +536,24 @@ func TestReflectReport_populateFindings(t *testing.T) {
 			},
 			mockedUsageReportRunQueryError: nil,
 			mockedErrorReportRunQueryError: nil,
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWS_111111111111_BreakGlass",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"sts.amazonaws.com/AssumeRole", 15},
 						{"sts.amazonaws.com/AssumeRole/AccessDenied", 15},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy", 1},
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/configuration-recorder-role",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey", 6},
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 6},
 					},
@@ -561,7 +564,7 @@ func TestReflectReport_populateFindings(t *testing.T) {
 		{
 			name:         "dataFromErrorQuery#4",
 			args:         args{"default.reflect_cloudTrail_test1"},
-			initFindings: []reflectFinding{},
+			initFindings: []ReflectFinding{},
 			flags: ReflectFlags{
 				usageReport: false,
 				errorReport: true,
@@ -631,22 +634,22 @@ func TestReflectReport_populateFindings(t *testing.T) {
 			},
 			mockedUsageReportRunQueryError: nil,
 			mockedErrorReportRunQueryError: nil,
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWS_111111111111_BreakGlass",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"sts.amazonaws.com/AssumeRole/AccessDenied", 15},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWS_111111111111_Read",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"iam.amazonaws.com/UpdateAssumeRolePolicy/AccessDenied", 1},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/configuration-recorder-role",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 6},
 					},
 				},
@@ -686,26 +689,26 @@ func TestReflectReport_populateFindings(t *testing.T) {
 func TestReflectReport_updateFinding(t *testing.T) {
 	type args struct {
 		identity string
-		eventD   accessDetails
+		eventD   AccessDetails
 	}
 	tests := []struct {
 		name            string
 		args            args
 		providedRoles   []string
-		initFindings    []reflectFinding
-		updatedFindings []reflectFinding
+		initFindings    []ReflectFinding
+		updatedFindings []ReflectFinding
 	}{
 		{
 			name: "simpleAdd#1",
 			args: args{
 				identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-				eventD:   accessDetails{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
+				eventD:   AccessDetails{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 			},
-			initFindings: []reflectFinding{},
-			updatedFindings: []reflectFinding{
+			initFindings: []ReflectFinding{},
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 					},
 				},
@@ -715,20 +718,20 @@ func TestReflectReport_updateFinding(t *testing.T) {
 			name: "simpleUpdate#2",
 			args: args{
 				identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-				eventD:   accessDetails{"kms.amazonaws.com/CreateKey", 5},
+				eventD:   AccessDetails{"kms.amazonaws.com/CreateKey", 5},
 			},
-			initFindings: []reflectFinding{
+			initFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 					},
 				},
 			},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
@@ -739,28 +742,28 @@ func TestReflectReport_updateFinding(t *testing.T) {
 			name: "addNewIdentity#3",
 			args: args{
 				identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-				eventD:   accessDetails{"kms.amazonaws.com/Decrypt", 7},
+				eventD:   AccessDetails{"kms.amazonaws.com/Decrypt", 7},
 			},
-			initFindings: []reflectFinding{
+			initFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 			},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 7},
 					},
 				},
@@ -770,34 +773,34 @@ func TestReflectReport_updateFinding(t *testing.T) {
 			name: "updateCount#4",
 			args: args{
 				identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-				eventD:   accessDetails{"kms.amazonaws.com/Decrypt", 13},
+				eventD:   AccessDetails{"kms.amazonaws.com/Decrypt", 13},
 			},
-			initFindings: []reflectFinding{
+			initFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 7},
 					},
 				},
 			},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 20},
 					},
 				},
@@ -807,35 +810,35 @@ func TestReflectReport_updateFinding(t *testing.T) {
 			name: "filterRole#5",
 			args: args{
 				identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-stage",
-				eventD:   accessDetails{"kms.amazonaws.com/Decrypt", 31},
+				eventD:   AccessDetails{"kms.amazonaws.com/Decrypt", 31},
 			},
 			providedRoles: []string{"arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer", "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev"},
-			initFindings: []reflectFinding{
+			initFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 7},
 					},
 				},
 			},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 7},
 					},
 				},
@@ -845,41 +848,41 @@ func TestReflectReport_updateFinding(t *testing.T) {
 			name: "filterRole#6",
 			args: args{
 				identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-stage",
-				eventD:   accessDetails{"kms.amazonaws.com/Decrypt", 31},
+				eventD:   AccessDetails{"kms.amazonaws.com/Decrypt", 31},
 			},
 			providedRoles: []string{"arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer", "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev", "arn:aws:iam::111111111111:role/web-gateway-greencherry-stage"},
-			initFindings: []reflectFinding{
+			initFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 7},
 					},
 				},
 			},
-			updatedFindings: []reflectFinding{
+			updatedFindings: []ReflectFinding{
 				{
 					Identity: "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 						{"kms.amazonaws.com/CreateKey", 5},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 7},
 					},
 				},
 				{
 					Identity: "arn:aws:iam::111111111111:role/web-gateway-greencherry-stage",
-					AccessDetails: []accessDetails{
+					AccessDetails: []AccessDetails{
 						{"kms.amazonaws.com/Decrypt", 31},
 					},
 				},
@@ -907,7 +910,7 @@ func Test_constructFinding(t *testing.T) {
 		name  string
 		args  args
 		want  string
-		want1 accessDetails
+		want1 AccessDetails
 	}{
 		{
 			name: "withErrorCode#1",
@@ -916,7 +919,7 @@ func Test_constructFinding(t *testing.T) {
 				keys:      []string{"arn", "eventsource", "eventname", "errorcode", "count"},
 			},
 			want:  "arn:aws:iam::111111111111:role/AWSServiceRoleForAccessAnalyzer",
-			want1: accessDetails{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
+			want1: AccessDetails{"kms.amazonaws.com/DescribeKey/AccessDenied", 18},
 		},
 		{
 			name: "withOutErrorCode#2",
@@ -925,7 +928,7 @@ func Test_constructFinding(t *testing.T) {
 				keys:      []string{"arn", "eventsource", "eventname", "count"},
 			},
 			want:  "arn:aws:iam::111111111111:role/AWSServiceRoleForAmazonInspector",
-			want1: accessDetails{"ec2.amazonaws.com/DescribeVpnGateways", 1},
+			want1: AccessDetails{"ec2.amazonaws.com/DescribeVpnGateways", 1},
 		},
 		{
 			name: "withIdentity#3",
@@ -934,7 +937,7 @@ func Test_constructFinding(t *testing.T) {
 				keys:      []string{"arn", "identity_arn", "eventsource", "eventname", "count"},
 			},
 			want:  "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev@aws-sdk-java-1606285491012",
-			want1: accessDetails{"kms.amazonaws.com/Decrypt", 3},
+			want1: AccessDetails{"kms.amazonaws.com/Decrypt", 3},
 		},
 		{
 			name: "withIdentityAndErrorCode#4",
@@ -943,7 +946,7 @@ func Test_constructFinding(t *testing.T) {
 				keys:      []string{"arn", "identity_arn", "eventsource", "eventname", "errorcode", "count"},
 			},
 			want:  "arn:aws:iam::111111111111:role/web-gateway-greencherry-dev@aws-sdk-java-1606285491012",
-			want1: accessDetails{"kms.amazonaws.com/DescribeKey/AccessDenied", 19},
+			want1: AccessDetails{"kms.amazonaws.com/DescribeKey/AccessDenied", 19},
 		},
 	}
 	for _, tt := range tests {
@@ -1269,3 +1272,26 @@ func Test_ConstructPartitionDataFromTime(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateAbsoluteTime(t *testing.T) {
+	errorMessage := errors.New("--absolute-time is wrong. It should be in the form 'startTime-endTime' 'mm/dd/yyyy-mm/dd/yyy' ex: '10/25/2020-10/31/2020'")
+	testCases := []struct {
+		name          string
+		absoluteTime  string
+		expectedError error
+	}{
+		{name: "Return no error without absolute time", absoluteTime: ""},
+		{name: "Return no error for valid absolute time", absoluteTime: "10/25/2020-10/31/2020"},
+		{name: "Return error for missing end time", absoluteTime: "10/25/2020", expectedError: errorMessage},
+		{name: "Return error for invalid date", absoluteTime: "2020/10/25-10/31/2020", expectedError: errorMessage},
+		{name: "Return error for end time before start time", absoluteTime: "10/31/2020-10/25/2020", expectedError: errorMessage},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := validateAbsoluteTime(tc.absoluteTime); !reflect.DeepEqual(err, tc.expectedError) {
+				t.Errorf("validateAbsoluteTime() error = %v, wantErr %v", err, tc.expectedError)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package cloudig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws/endpoints"
+)
+
+// allRegions is the value of the regions flag to run regional reports in every region of the partition
+const allRegions string = "all"
+
+// ResolveRegions returns the list of regions to run the regional reports in from a comma separated list of regions,
+// or every region of the partition of defaultRegion when regions is "all". defaultRegion is used when regions is empty
+func ResolveRegions(regions string, defaultRegion string) ([]string, error) {
+	partition, ok := endpoints.PartitionForRegion(endpoints.DefaultPartitions(), defaultRegion)
+	if !ok {
+		return nil, fmt.Errorf("unknown region '%s'", defaultRegion)
+	}
+	partitionRegions := getPartitionRegions(partition)
+
+	if regions == "" {
+		return []string{defaultRegion}, nil
+	}
+	if regions == allRegions {
+		return partitionRegions, nil
+	}
+
+	regionList := make([]string, 0)
+	for _, region := range strings.Split(regions, ",") {
+		region = strings.TrimSpace(region)
+		if !Contains(partitionRegions, region) {
+			return nil, fmt.Errorf("unknown region '%s' in partition '%s'", region, partition.ID())
+		}
+		if !Contains(regionList, region) {
+			regionList = append(regionList, region)
+		}
+	}
+	sort.Strings(regionList)
+	return regionList, nil
+}
+
+// getPartitionRegions returns the sorted list of regions in the given partitions
+func getPartitionRegions(partitions ...endpoints.Partition) []string {
+	regionList := make([]string, 0)
+	for _, p := range partitions {
+		for region := range p.Regions() {
+			regionList = append(regionList, region)
+		}
+	}
+	sort.Strings(regionList)
+	return regionList
+}
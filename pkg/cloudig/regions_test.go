@@ -0,0 +1,57 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveRegions(t *testing.T) {
+	testCases := []struct {
+		name           string
+		regions        string
+		defaultRegion  string
+		expectedOutput []string
+		expectedError  error
+	}{
+		{
+			name:           "Return default region when no regions provided",
+			regions:        "",
+			defaultRegion:  "us-east-1",
+			expectedOutput: []string{"us-east-1"},
+		},
+		{
+			name:           "Return sorted unique regions",
+			regions:        "us-west-2, us-east-1,us-west-2",
+			defaultRegion:  "us-east-1",
+			expectedOutput: []string{"us-east-1", "us-west-2"},
+		},
+		{
+			name:           "Return all regions of the partition",
+			regions:        "all",
+			defaultRegion:  "us-gov-west-1",
+			expectedOutput: []string{"us-gov-east-1", "us-gov-west-1"},
+		},
+		{
+			name:          "Return error for region outside of the partition",
+			regions:       "us-east-1",
+			defaultRegion: "us-gov-west-1",
+			expectedError: errors.New("unknown region 'us-east-1' in partition 'aws-us-gov'"),
+		},
+		{
+			name:          "Return error for unknown region",
+			regions:       "us-east-1,mars-1",
+			defaultRegion: "us-east-1",
+			expectedError: errors.New("unknown region 'mars-1' in partition 'aws'"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := ResolveRegions(tc.regions, tc.defaultRegion)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package cloudig
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+)
+
+// Parent commands of the report types
+const (
+	CommandGet     string = "get"
+	CommandReflect string = "reflect"
+)
+
+// ReportOptions are the global options a report type can be configured with
+type ReportOptions struct {
+	// Region is the region of the session
+	Region string
+	// Regions are the regions given with --regions, empty when none or all the regions of the partition are used
+	Regions []string
+	// Values holds the values of the options of the report type by option name, of the type of their default
+	Values map[string]interface{}
+}
+
+// GetString returns the value of a string option, "" when it has none
+func (options ReportOptions) GetString(name string) string {
+	value, _ := options.Values[name].(string)
+	return value
+}
+
+// GetBool returns the value of a bool option, false when it has none
+func (options ReportOptions) GetBool(name string) bool {
+	value, _ := options.Values[name].(bool)
+	return value
+}
+
+// GetInt returns the value of an int option, 0 when it has none
+func (options ReportOptions) GetInt(name string) int {
+	value, _ := options.Values[name].(int)
+	return value
+}
+
+// ReportOption describes an option specific to a report type. The commands of the report bind it to a flag and pass
+// its value to New in ReportOptions.Values
+type ReportOption struct {
+	// Name is the name of the option and of its flag, ex: "pastdays"
+	Name string
+	// Shorthand is the one letter shorthand of the flag, empty when none
+	Shorthand string
+	// Default is the value of the option when it is not given. Its type, string, bool or int, is the type of the option
+	Default interface{}
+	Usage   string
+}
+
+// ReportType describes a type of report. Commands are generated for every registered report type, so adding a report
+// only takes implementing Report and registering its type from an init function of its own file
+type ReportType struct {
+	// Name identifies the report in the composite outputs and the errors section, ex: "trustedadvisor"
+	Name string
+	// Title is the heading of the report in the composite table outputs, ex: "Trusted Advisor"
+	Title string
+	// Command is the parent command of the report, CommandGet or CommandReflect
+	Command string
+	// Use is the name of the subcommand, Name when empty
+	Use     string
+	Aliases []string
+	Short   string
+	// CommentsKey is the key of the section of the comments file holding the comments of the findings, ex: "ta-findings"
+	CommentsKey string
+	// CommentsKeyFormat validates the keys of the comments section in comments lint, nil accepts any key
+	CommentsKeyFormat *regexp.Regexp
+	// Report is an empty report of the type, used to find the type of a report
+	Report Report
+	// FailOnKeys are the severities or statuses the findings can be counted by in the fail-on conditions, matched case
+	// insensitively, ex: "CRITICAL". Nil when the report only supports the "new" condition
+	FailOnKeys []string
+	// CountFindings counts the findings of a report of the type having a fail-on key, or the new findings for
+	// commentNewFinding. Nil when the findings don't count for the fail-on conditions
+	CountFindings func(report Report, key string) int
+	// Options are the options specific to the report, nil when there is none
+	Options []ReportOption
+	// Table renders a report of the type as an ASCII table or a markdown table, see tableTypeNormal and tableTypeMD
+	Table func(report Report, tableType string) string
+	// New returns a new report configured with the global options and the values of Options
+	New func(options ReportOptions) (Report, error)
+}
+
+var (
+	reportTypesMu sync.RWMutex
+	reportTypes   = make([]ReportType, 0)
+)
+
+// RegisterReportType adds a report type to the registry. It panics when a report type with the same name, or the same
+// subcommand under the same parent command, is already registered
+func RegisterReportType(reportType ReportType) {
+	reportTypesMu.Lock()
+	defer reportTypesMu.Unlock()
+	if reportType.Use == "" {
+		reportType.Use = reportType.Name
+	}
+	for _, t := range reportTypes {
+		if t.Name == reportType.Name || (t.Command == reportType.Command && t.Use == reportType.Use) {
+			panic(fmt.Sprintf("report type '%s' is already registered", reportType.Name))
+		}
+	}
+	reportTypes = append(reportTypes, reportType)
+}
+
+// GetReportTypes returns the registered report types of the parent command in the order they were registered, every
+// report type when command is empty
+func GetReportTypes(command string) []ReportType {
+	reportTypesMu.RLock()
+	defer reportTypesMu.RUnlock()
+	types := make([]ReportType, 0, len(reportTypes))
+	for _, t := range reportTypes {
+		if command == "" || t.Command == command {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// GetReportType returns the report type of the parent command with the given name, subcommand or alias
+func GetReportType(command string, name string) (ReportType, bool) {
+	for _, t := range GetReportTypes(command) {
+		if t.Name == name || t.Use == name || Contains(t.Aliases, name) {
+			return t, true
+		}
+	}
+	return ReportType{}, false
+}
+
+// getReportTypeOf returns the registered report type of a report
+func getReportTypeOf(report Report) (ReportType, bool) {
+	for _, t := range GetReportTypes("") {
+		if reflect.TypeOf(t.Report) == reflect.TypeOf(report) {
+			return t, true
+		}
+	}
+	return ReportType{}, false
+}
+
+// GetCommentsKeys returns the comments section key of the report type of a report, of each report of a composite
+func GetCommentsKeys(report Report) []string {
+	keys := make([]string, 0)
+	if composite, ok := report.(*CompositeReport); ok {
+		for _, r := range composite.Reports {
+			keys = append(keys, GetCommentsKeys(r)...)
+		}
+		return keys
+	}
+	if t, ok := getReportTypeOf(report); ok && t.CommentsKey != "" {
+		keys = append(keys, t.CommentsKey)
+	}
+	return keys
+}
@@ -0,0 +1,128 @@
+package cloudig
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestGetReportType(t *testing.T) {
+	testCases := []struct {
+		name         string
+		command      string
+		reportName   string
+		expectedName string
+		expectedOK   bool
+	}{
+		{
+			name:         "Return report type by name",
+			command:      CommandGet,
+			reportName:   "trustedadvisor",
+			expectedName: "trustedadvisor",
+			expectedOK:   true,
+		},
+		{
+			name:         "Return report type by alias",
+			command:      CommandGet,
+			reportName:   "config",
+			expectedName: "awsconfig",
+			expectedOK:   true,
+		},
+		{
+			name:         "Return report type by subcommand",
+			command:      CommandReflect,
+			reportName:   "iam",
+			expectedName: "reflectiam",
+			expectedOK:   true,
+		},
+		{
+			name:       "Return nothing for a report type of another command",
+			command:    CommandGet,
+			reportName: "iam",
+			expectedOK: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reportType, ok := GetReportType(tc.command, tc.reportName)
+			assert.Equal(t, tc.expectedOK, ok)
+			assert.Equal(t, tc.expectedName, reportType.Name)
+		})
+	}
+}
+
+func TestGetReportTypes(t *testing.T) {
+	names := make([]string, 0)
+	for _, reportType := range GetReportTypes(CommandGet) {
+		names = append(names, reportType.Name)
+	}
+	assert.ElementsMatch(t, []string{"trustedadvisor", "awsconfig", "inspector", "health", "ecrscan"}, names)
+	assert.Len(t, GetReportTypes(""), 6)
+}
+
+func TestRegisterReportType(t *testing.T) {
+	assert.Panics(t, func() {
+		RegisterReportType(ReportType{Name: "trustedadvisor", Command: CommandGet})
+	})
+	assert.Panics(t, func() {
+		RegisterReportType(ReportType{Name: "ta2", Command: CommandReflect, Use: "iam"})
+	})
+}
+
+func TestReportTypeNew(t *testing.T) {
+	reportType, _ := GetReportType(CommandGet, "health")
+	report, err := reportType.New(ReportOptions{Region: "us-east-1", Regions: []string{"us-east-1"}, Values: map[string]interface{}{"exclude-regions": "us-west-2", "pastdays": "7"}})
+	assert.NoError(t, err)
+	assert.Equal(t, HealthReportFlags{
+		PastDays:       "7",
+		ExcludeRegions: []string{"us-west-2"},
+		IncludeRegions: []string{},
+		Regions:        []string{"us-east-1", "global"},
+	}, report.(*HealthReport).Flags)
+	assert.Equal(t, "health", getReportName(report))
+	assert.Equal(t, "Health", getReportTitle(report))
+	assert.Equal(t, report.(*HealthReport).toTable(tableTypeMD), renderTable(report, tableTypeMD))
+}
+
+func TestReportTypeOptions(t *testing.T) {
+	for _, reportType := range GetReportTypes("") {
+		assert.NotNil(t, reportType.Table, reportType.Name)
+		for _, option := range reportType.Options {
+			switch option.Default.(type) {
+			case string, bool, int:
+			default:
+				t.Errorf("option '%s' of report '%s' has a default of unsupported type %T", option.Name, reportType.Name, option.Default)
+			}
+		}
+	}
+
+	options := ReportOptions{Values: map[string]interface{}{"pastdays": "7", "details": true, "relative-time": 3}}
+	assert.Equal(t, "7", options.GetString("pastdays"))
+	assert.True(t, options.GetBool("details"))
+	assert.Equal(t, 3, options.GetInt("relative-time"))
+	assert.Equal(t, "", options.GetString("tag"))
+}
+
+func TestCommentsSections(t *testing.T) {
+	content := `
+- accountid: "111111111111"
+  ta-findings:
+    - SECURITY-IAM_Use: "Known exception"
+  custom-findings:
+    - my-finding: "Custom exception"
+`
+	var comments []Comments
+	assert.NoError(t, yaml.Unmarshal([]byte(content), &comments))
+	assert.Equal(t, []Comments{
+		{
+			AccountID:  "111111111111",
+			TAFindings: []map[string]Comment{{"SECURITY-IAM_Use": {Text: "Known exception"}}},
+			Sections:   map[string][]map[string]Comment{"custom-findings": {{"my-finding": {Text: "Custom exception"}}}},
+		},
+	}, comments)
+	assert.Equal(t, "Custom exception", getComments(comments, "111111111111", "custom-findings", "my-finding").String())
+	assert.Equal(t, "Known exception", getComments(comments, "111111111111", findingTypeTrustedAdvisor, "SECURITY-IAM_Use").String())
+	assert.Equal(t, "NEW_FINDING", getComments(comments, "111111111111", "custom-findings", "other-finding").String())
+}
@@ -0,0 +1,260 @@
+package cloudig
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kris-nova/logger"
+)
+
+// Version and schema of the SARIF log, and the tool of its runs
+const (
+	sarifVersion  string = "2.1.0"
+	sarifSchema   string = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifToolName string = "cloudig"
+	sarifToolURI  string = "https://github.com/Optum/cloudig"
+)
+
+// Levels of the SARIF results
+const (
+	sarifLevelError   string = "error"
+	sarifLevelWarning string = "warning"
+	sarifLevelNote    string = "note"
+)
+
+// sarifReport is implemented by the reports that can be output as SARIF
+type sarifReport interface {
+	// toSARIF returns a run per image or assessment of the report
+	toSARIF() []sarifRun
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool              sarifTool              `json:"tool"`
+	AutomationDetails sarifAutomationDetails `json:"automationDetails"`
+	Results           []sarifResult          `json:"results"`
+	Properties        map[string]string      `json:"properties,omitempty"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+// sarifAutomationDetails identifies the run, so that the runs of the images and assessments are told apart
+type sarifAutomationDetails struct {
+	ID string `json:"id"`
+}
+
+type sarifRule struct {
+	ID                   string                 `json:"id"`
+	Name                 string                 `json:"name"`
+	ShortDescription     sarifMessage           `json:"shortDescription"`
+	DefaultConfiguration sarifRuleConfiguration `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties,omitempty"`
+}
+
+type sarifRuleConfiguration struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID       string             `json:"ruleId"`
+	Level        string             `json:"level"`
+	Message      sarifMessage       `json:"message"`
+	Locations    []sarifLocation    `json:"locations"`
+	Suppressions []sarifSuppression `json:"suppressions,omitempty"`
+	Properties   map[string]string  `json:"properties,omitempty"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifSuppression struct {
+	Kind          string `json:"kind"`
+	Status        string `json:"status"`
+	Justification string `json:"justification"`
+}
+
+// toSARIF outputs a run per image, with a result per severity of the vulnerabilities found in the image
+func (report *ImageScanReports) toSARIF() []sarifRun {
+	runs := make([]sarifRun, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		imageURI := getRepositoryURI(finding)
+		if finding.ImageTag != "" {
+			imageURI += ":" + strings.Split(finding.ImageTag, ",")[0]
+		} else {
+			imageURI += "@" + finding.ImageDigest
+		}
+		run := newSARIFRun(getReportName(report)+"/"+imageURI+"/", finding.AccountID, finding.Region)
+		for _, severity := range ecrSeverities {
+			count := finding.ImageFindingsCount[severity]
+			if count == 0 {
+				continue
+			}
+			ruleID := "ECR/" + severity
+			run.addRule(ruleID, severity+" vulnerabilities", severity+" vulnerabilities found by the ECR image scan", severity)
+			run.Results = append(run.Results, newSARIFResult(ruleID, severity, fmt.Sprintf("%d %s vulnerabilities in image %s", count, severity, imageURI), imageURI, count, finding.Comments, finding.Owner, finding.Ticket))
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// toSARIF outputs a run per assessment, with a result per rule package and severity of the findings of the assessment
+func (reports *InspectorReports) toSARIF() []sarifRun {
+	runs := make([]sarifRun, 0, len(reports.Reports))
+	for _, report := range reports.Reports {
+		assessment := report.AccountID + "/" + report.Region + "/" + report.TemplateName
+		run := newSARIFRun(getReportName(reports)+"/"+assessment+"/", report.AccountID, report.Region)
+		run.Properties["templateName"] = report.TemplateName
+		for _, finding := range report.Findings {
+			for _, severity := range inspectorSeverities {
+				count := getInspectorSeverityCount(finding, severity)
+				if count == 0 {
+					continue
+				}
+				ruleID := finding.RulePackageName + "/" + severity
+				run.addRule(ruleID, finding.RulePackageName+" "+severity, severity+" findings of the Inspector rule package "+finding.RulePackageName, severity)
+				run.Results = append(run.Results, newSARIFResult(ruleID, severity, fmt.Sprintf("%d %s findings of rule package %s in assessment %s", count, severity, finding.RulePackageName, report.TemplateName), assessment, int64(count), finding.Comments, finding.Owner, finding.Ticket))
+			}
+		}
+		runs = append(runs, run)
+	}
+	return runs
+}
+
+// toSARIF outputs the runs of the reports of the composite that can be output as SARIF, the other reports are left out
+func (report *CompositeReport) toSARIF() []sarifRun {
+	runs := make([]sarifRun, 0)
+	for _, child := range report.Reports {
+		if r, ok := child.(sarifReport); ok {
+			runs = append(runs, r.toSARIF()...)
+		}
+	}
+	return runs
+}
+
+func supportsSARIF(report Report) bool {
+	if composite, ok := report.(*CompositeReport); ok {
+		for _, child := range composite.Reports {
+			if supportsSARIF(child) {
+				return true
+			}
+		}
+		return false
+	}
+	_, ok := report.(sarifReport)
+	return ok
+}
+
+// toSARIFLog renders the runs of a report as a SARIF log, without runs for the reports that can't be output as SARIF
+func toSARIFLog(report Report) string {
+	log := sarifLog{Schema: sarifSchema, Version: sarifVersion, Runs: make([]sarifRun, 0)}
+	if r, ok := report.(sarifReport); ok {
+		log.Runs = r.toSARIF()
+	}
+	content, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		logger.Critical("unable to marshal the output into SARIF: %v", err)
+	}
+	return string(content)
+}
+
+func newSARIFRun(id string, accountID string, region string) sarifRun {
+	return sarifRun{
+		Tool:              sarifTool{Driver: sarifDriver{Name: sarifToolName, InformationURI: sarifToolURI, Rules: make([]sarifRule, 0)}},
+		AutomationDetails: sarifAutomationDetails{ID: id},
+		Results:           make([]sarifResult, 0),
+		Properties:        map[string]string{"accountId": accountID, "region": region},
+	}
+}
+
+// addRule adds the rule of a severity to the driver of the run once
+func (run *sarifRun) addRule(id string, name string, description string, severity string) {
+	for _, rule := range run.Tool.Driver.Rules {
+		if rule.ID == id {
+			return
+		}
+	}
+	rule := sarifRule{ID: id, Name: name, ShortDescription: sarifMessage{Text: description}, DefaultConfiguration: sarifRuleConfiguration{Level: getSARIFLevel(severity)}}
+	if score, ok := sarifSecuritySeverities[strings.ToUpper(severity)]; ok {
+		// security-severity sorts the results of GitHub code scanning into critical, high, medium and low
+		rule.Properties = map[string]interface{}{"security-severity": score, "tags": []string{"security"}}
+	}
+	run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+}
+
+// sarifSecuritySeverities are the security-severity scores of the severities
+var sarifSecuritySeverities = map[string]string{"CRITICAL": "9.5", "HIGH": "8.0", "MEDIUM": "5.5", "LOW": "2.0"}
+
+// newSARIFResult returns the result of the count of findings of a severity. Findings with an EXCEPTION comment are
+// suppressed with the comment as justification
+func newSARIFResult(ruleID string, severity string, message string, uri string, count int64, comments string, owner string, ticket string) sarifResult {
+	result := sarifResult{
+		RuleID:     ruleID,
+		Level:      getSARIFLevel(severity),
+		Message:    sarifMessage{Text: message},
+		Locations:  []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: uri}}}},
+		Properties: map[string]string{"count": strconv.FormatInt(count, 10), "comments": comments},
+	}
+	if owner != "" {
+		result.Properties["owner"] = owner
+	}
+	if ticket != "" {
+		result.Properties["ticket"] = ticket
+	}
+	if justification, ok := getExceptionJustification(comments); ok {
+		result.Suppressions = []sarifSuppression{{Kind: "external", Status: "accepted", Justification: justification}}
+	}
+	return result
+}
+
+// getSARIFLevel maps a severity to a SARIF level: error for critical and high, warning for medium, note otherwise
+func getSARIFLevel(severity string) string {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL", "HIGH":
+		return sarifLevelError
+	case "MEDIUM":
+		return sarifLevelWarning
+	default:
+		return sarifLevelNote
+	}
+}
+
+// getExceptionJustification returns the text of an EXCEPTION comment, rendered as "**EXCEPTION:** reason"
+func getExceptionJustification(comments string) (string, bool) {
+	prefix := "**" + CommentStatusException + ":**"
+	switch {
+	case comments == CommentStatusException:
+		return comments, true
+	case strings.HasPrefix(comments, prefix):
+		return strings.TrimSpace(strings.TrimPrefix(comments, prefix)), true
+	}
+	return "", false
+}
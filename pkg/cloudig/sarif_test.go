@@ -0,0 +1,252 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSARIFOutput(t *testing.T) {
+	testCases := []struct {
+		name           string
+		report         Report
+		expectedOutput string
+	}{
+		{
+			name: "returnRunPerImage#1",
+			report: &ImageScanReports{Findings: []ImageScanFindings{
+				{AccountID: "111111111111", Region: "us-east-1", RepositoryName: "app/web", ImageTag: "v1,latest", ImageFindingsCount: map[string]int64{"CRITICAL": 1, "LOW": 3}, Comments: "**EXCEPTION:** Patch is coming", Owner: "team-a"},
+			}},
+			expectedOutput: `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "cloudig",
+          "informationUri": "https://github.com/Optum/cloudig",
+          "rules": [
+            {
+              "id": "ECR/CRITICAL",
+              "name": "CRITICAL vulnerabilities",
+              "shortDescription": {
+                "text": "CRITICAL vulnerabilities found by the ECR image scan"
+              },
+              "defaultConfiguration": {
+                "level": "error"
+              },
+              "properties": {
+                "security-severity": "9.5",
+                "tags": [
+                  "security"
+                ]
+              }
+            },
+            {
+              "id": "ECR/LOW",
+              "name": "LOW vulnerabilities",
+              "shortDescription": {
+                "text": "LOW vulnerabilities found by the ECR image scan"
+              },
+              "defaultConfiguration": {
+                "level": "note"
+              },
+              "properties": {
+                "security-severity": "2.0",
+                "tags": [
+                  "security"
+                ]
+              }
+            }
+          ]
+        }
+      },
+      "automationDetails": {
+        "id": "ecrscan/111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web:v1/"
+      },
+      "results": [
+        {
+          "ruleId": "ECR/CRITICAL",
+          "level": "error",
+          "message": {
+            "text": "1 CRITICAL vulnerabilities in image 111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web:v1"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web:v1"
+                }
+              }
+            }
+          ],
+          "suppressions": [
+            {
+              "kind": "external",
+              "status": "accepted",
+              "justification": "Patch is coming"
+            }
+          ],
+          "properties": {
+            "comments": "**EXCEPTION:** Patch is coming",
+            "count": "1",
+            "owner": "team-a"
+          }
+        },
+        {
+          "ruleId": "ECR/LOW",
+          "level": "note",
+          "message": {
+            "text": "3 LOW vulnerabilities in image 111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web:v1"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "111111111111.dkr.ecr.us-east-1.amazonaws.com/app/web:v1"
+                }
+              }
+            }
+          ],
+          "suppressions": [
+            {
+              "kind": "external",
+              "status": "accepted",
+              "justification": "Patch is coming"
+            }
+          ],
+          "properties": {
+            "comments": "**EXCEPTION:** Patch is coming",
+            "count": "3",
+            "owner": "team-a"
+          }
+        }
+      ],
+      "properties": {
+        "accountId": "111111111111",
+        "region": "us-east-1"
+      }
+    }
+  ]
+}`,
+		},
+		{
+			name: "returnRunPerAssessmentOfComposite#2",
+			report: &CompositeReport{Reports: []Report{
+				&HealthReport{Findings: []HealthReportFinding{{AccountID: "111111111111", EventTypeCode: "AWS_EC2_OPERATIONAL_ISSUE", Comments: "NEW_FINDING"}}},
+				&InspectorReports{Reports: []InspectorReport{
+					{AccountID: "111111111111", Region: "us-east-1", TemplateName: "weekly", Findings: []InspectorReportFinding{{RulePackageName: "CIS Benchmarks-1.0", High: "2", Medium: "0", Low: "0", Informational: "0", Comments: "NEW_FINDING"}}},
+				}},
+			}},
+			expectedOutput: `{
+  "$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+  "version": "2.1.0",
+  "runs": [
+    {
+      "tool": {
+        "driver": {
+          "name": "cloudig",
+          "informationUri": "https://github.com/Optum/cloudig",
+          "rules": [
+            {
+              "id": "CIS Benchmarks-1.0/High",
+              "name": "CIS Benchmarks-1.0 High",
+              "shortDescription": {
+                "text": "High findings of the Inspector rule package CIS Benchmarks-1.0"
+              },
+              "defaultConfiguration": {
+                "level": "error"
+              },
+              "properties": {
+                "security-severity": "8.0",
+                "tags": [
+                  "security"
+                ]
+              }
+            }
+          ]
+        }
+      },
+      "automationDetails": {
+        "id": "inspector/111111111111/us-east-1/weekly/"
+      },
+      "results": [
+        {
+          "ruleId": "CIS Benchmarks-1.0/High",
+          "level": "error",
+          "message": {
+            "text": "2 High findings of rule package CIS Benchmarks-1.0 in assessment weekly"
+          },
+          "locations": [
+            {
+              "physicalLocation": {
+                "artifactLocation": {
+                  "uri": "111111111111/us-east-1/weekly"
+                }
+              }
+            }
+          ],
+          "properties": {
+            "comments": "NEW_FINDING",
+            "count": "2"
+          }
+        }
+      ],
+      "properties": {
+        "accountId": "111111111111",
+        "region": "us-east-1",
+        "templateName": "weekly"
+      }
+    }
+  ]
+}`,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			output, err := RenderReport(tc.report, OutputTypeSARIF, []ReportError{})
+			assert.NoError(t, err)
+			assert.Equal(t, tc.expectedOutput, output)
+		})
+	}
+}
+
+func TestGetSARIFLevel(t *testing.T) {
+	assert.Equal(t, "error", getSARIFLevel("CRITICAL"))
+	assert.Equal(t, "error", getSARIFLevel("High"))
+	assert.Equal(t, "warning", getSARIFLevel("MEDIUM"))
+	assert.Equal(t, "note", getSARIFLevel("Low"))
+	assert.Equal(t, "note", getSARIFLevel("INFORMATIONAL"))
+	assert.Equal(t, "note", getSARIFLevel("UNDEFINED"))
+}
+
+func TestGetExceptionJustification(t *testing.T) {
+	testCases := []struct {
+		comments              string
+		expectedJustification string
+		expectedOK            bool
+	}{
+		{comments: "**EXCEPTION:** Patch is coming", expectedJustification: "Patch is coming", expectedOK: true},
+		{comments: "EXCEPTION", expectedJustification: "EXCEPTION", expectedOK: true},
+		{comments: "**ACCEPTED_RISK:** Internal only"},
+		{comments: "EXPIRED_EXCEPTION"},
+		{comments: "NEW_FINDING"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.comments, func(t *testing.T) {
+			justification, ok := getExceptionJustification(tc.comments)
+			assert.Equal(t, tc.expectedJustification, justification)
+			assert.Equal(t, tc.expectedOK, ok)
+		})
+	}
+}
+
+func TestCheckOutputType(t *testing.T) {
+	assert.NoError(t, CheckOutputType(&ImageScanReports{}, OutputTypeSARIF))
+	assert.NoError(t, CheckOutputType(&CompositeReport{Reports: []Report{&HealthReport{}, &InspectorReports{}}}, OutputTypeSARIF))
+	assert.NoError(t, CheckOutputType(&HealthReport{}, OutputTypeJSON))
+	assert.Equal(t, errors.New("sarif output is only supported by the ecrscan and inspector reports"), CheckOutputType(&HealthReport{}, OutputTypeSARIF))
+	assert.Equal(t, errors.New("sarif output is only supported by the ecrscan and inspector reports"), CheckOutputType(&CompositeReport{Reports: []Report{&ConfigReport{}}}, OutputTypeSARIF))
+}
@@ -0,0 +1,184 @@
+package cloudig
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v2"
+)
+
+// CommentKeys records the comments file keys of the findings by account ID and comments section, to scaffold a
+// comments file from the findings without comment or lint it against every finding. It is safe to use from the go
+// routines collecting the accounts
+type CommentKeys struct {
+	mu sync.Mutex
+	// keys holds the keys of the findings without comment
+	keys map[string]map[string][]string
+	// findings holds the keys of every finding
+	findings map[string]map[string][]string
+	// collected holds the accounts the findings were collected for, the keys of the other accounts can't be stale
+	collected map[string]bool
+}
+
+// NewCommentKeys returns an empty record of comment keys
+func NewCommentKeys() *CommentKeys {
+	return &CommentKeys{keys: make(map[string]map[string][]string), findings: make(map[string]map[string][]string), collected: make(map[string]bool)}
+}
+
+// AddCollectedAccounts records the accounts the findings were collected for, including those without findings
+func (k *CommentKeys) AddCollectedAccounts(accountIDs ...string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, accountID := range accountIDs {
+		k.collected[accountID] = true
+	}
+}
+
+// Record records the keys of the findings of a report, ex: a collected report or a report read with ParseReportJSON
+func (k *CommentKeys) Record(report Report) {
+	report.recordCommentKeys(k)
+}
+
+// AddReportAccounts records the accounts with findings in a report as collected, ex: of a report read with
+// ParseReportJSON
+func (k *CommentKeys) AddReportAccounts(report Report) {
+	k.AddCollectedAccounts(report.accountIDs()...)
+}
+
+// Get returns the recorded keys of the findings without comment of the comments section of an account, in the order
+// they were recorded
+func (k *CommentKeys) Get(accountID string, section string) []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return append([]string(nil), k.keys[accountID][section]...)
+}
+
+// record adds the key of a finding once, to the keys without comment as well when isNew
+func (k *CommentKeys) record(accountID string, section string, key string, isNew bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	addKey(k.findings, accountID, section, key)
+	if isNew {
+		addKey(k.keys, accountID, section, key)
+	}
+}
+
+// hasFinding tells whether a finding of the comments section of an account matches the key of a comment, the
+// findings of every account when accountID is "*"
+func (k *CommentKeys) hasFinding(accountID string, section string, key string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for account, sections := range k.findings {
+		if accountID != commentAllAccounts && account != accountID {
+			continue
+		}
+		for _, findingKey := range sections[section] {
+			if findingKey == key || matchCommentKey(key, section, findingKey) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isCollected tells whether the findings of every account were collected, "*" being collected when any account was
+func (k *CommentKeys) isCollected(accountIDs []string) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	for _, accountID := range accountIDs {
+		if (accountID == commentAllAccounts && len(k.collected) == 0) || (accountID != commentAllAccounts && !k.collected[accountID]) {
+			return false
+		}
+	}
+	return true
+}
+
+func addKey(keys map[string]map[string][]string, accountID string, section string, key string) {
+	if keys[accountID] == nil {
+		keys[accountID] = make(map[string][]string)
+	}
+	if !Contains(keys[accountID][section], key) {
+		keys[accountID][section] = append(keys[accountID][section], key)
+	}
+}
+
+// accountIDs returns the accounts with recorded keys, sorted
+func (k *CommentKeys) accountIDs() []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	accountIDs := make([]string, 0, len(k.keys))
+	for accountID := range k.keys {
+		accountIDs = append(accountIDs, accountID)
+	}
+	sort.Strings(accountIDs)
+	return accountIDs
+}
+
+// sections returns the comments sections of an account with recorded keys, sorted
+func (k *CommentKeys) sections(accountID string) []string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	sections := make([]string, 0, len(k.keys[accountID]))
+	for section := range k.keys[accountID] {
+		sections = append(sections, section)
+	}
+	sort.Strings(sections)
+	return sections
+}
+
+// ScaffoldComments appends the recorded keys missing from the content of a comments file, with empty comments, and
+// returns the new content with the number of keys added. The content is kept as is, along with its YAML comments and
+// formatting: the missing keys are appended as a new entry per account. A key is missing when no entry whose accountid
+// is the account, or a list of accounts including it, has it
+func ScaffoldComments(content []byte, keys *CommentKeys) ([]byte, int, error) {
+	var comments []Comments
+	if err := yaml.Unmarshal(content, &comments); err != nil {
+		return nil, 0, fmt.Errorf("unable to parse comments: %v", err)
+	}
+	entries := make([]yaml.MapSlice, 0)
+	added := 0
+	for _, accountID := range keys.accountIDs() {
+		entry := yaml.MapSlice{{Key: "accountid", Value: accountID}}
+		for _, section := range keys.sections(accountID) {
+			items := make([]yaml.MapSlice, 0)
+			for _, key := range keys.Get(accountID, section) {
+				if !hasCommentKey(comments, accountID, section, key) {
+					items = append(items, yaml.MapSlice{{Key: key, Value: ""}})
+				}
+			}
+			if len(items) != 0 {
+				entry = append(entry, yaml.MapItem{Key: section, Value: items})
+				added += len(items)
+			}
+		}
+		if len(entry) > 1 {
+			entries = append(entries, entry)
+		}
+	}
+	if added == 0 {
+		return content, 0, nil
+	}
+	out, err := yaml.Marshal(entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	scaffolded := append([]byte(nil), content...)
+	if len(scaffolded) != 0 && scaffolded[len(scaffolded)-1] != '\n' {
+		scaffolded = append(scaffolded, '\n')
+	}
+	return append(scaffolded, out...), added, nil
+}
+
+// hasCommentKey tells whether an entry of the account has the key in the comments section
+func hasCommentKey(comments []Comments, accountID string, section string, key string) bool {
+	for _, entry := range comments {
+		if entry.matchAccount(accountID) != 2 {
+			continue
+		}
+		if _, ok := findComment(entry.getSection(section), key); ok {
+			return true
+		}
+	}
+	return false
+}
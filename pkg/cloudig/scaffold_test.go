@@ -0,0 +1,129 @@
+package cloudig
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v2"
+)
+
+func TestCommentKeysRecord(t *testing.T) {
+	keys := NewCommentKeys()
+	keys.Record(&CompositeReport{Reports: []Report{
+		&HealthReport{Findings: []HealthReportFinding{
+			{AccountID: "111111111111", EventTypeCode: "RDS_SECURITY_NOTIFICATION", Comments: "**EXCEPTION:** Description here"},
+			{AccountID: "111111111111", EventTypeCode: "EC2_OPERATIONAL_ISSUE", Comments: "NEW_FINDING"},
+			{AccountID: "111111111111", EventTypeCode: "EC2_OPERATIONAL_ISSUE", Comments: "NEW_FINDING"},
+		}},
+		&TrustedAdvisorReport{Findings: []TrustedAdvisorFinding{{AccountID: "333333333333", Category: "SECURITY", Name: "IAM Use", Comments: "NEW_FINDING"}}},
+	}})
+
+	assert.Equal(t, []string{"AWS_EC2_OPERATIONAL_ISSUE"}, keys.Get("111111111111", findingTypeAWSHealth))
+	assert.Equal(t, []string{"SECURITY-IAM_Use"}, keys.Get("333333333333", findingTypeTrustedAdvisor))
+	assert.Empty(t, keys.Get("222222222222", findingTypeTrustedAdvisor))
+	// the commented findings are recorded for lint only
+	assert.True(t, keys.hasFinding("111111111111", findingTypeAWSHealth, "AWS_RDS_SECURITY_NOTIFICATION"))
+}
+
+func TestScaffoldComments(t *testing.T) {
+	testCases := []struct {
+		name           string
+		content        string
+		keys           map[string]map[string][]string
+		expectedOutput string
+		expectedAdded  int
+		expectedError  error
+	}{
+		{
+			name:    "Return new file",
+			content: "",
+			keys: map[string]map[string][]string{
+				"111111111111": {findingTypeTrustedAdvisor: {"SECURITY-IAM_Use", "FAULT_TOLERANCE-Amazon_EBS_Snapshots"}, findingTypeAWSConfig: {"IAM_PASSWORD_POLICY"}},
+			},
+			expectedOutput: `- accountid: "111111111111"
+  config-findings:
+  - IAM_PASSWORD_POLICY: ""
+  ta-findings:
+  - SECURITY-IAM_Use: ""
+  - FAULT_TOLERANCE-Amazon_EBS_Snapshots: ""
+`,
+			expectedAdded: 3,
+		},
+		{
+			name: "Return existing entries as is with the missing keys appended",
+			content: `# exceptions reviewed by the security team
+- accountid: "*"
+  ta-findings:
+  - SECURITY-*: '**EXCEPTION:** Security is handled by the security team'
+- accountid: [111111111111, 444444444444]
+  ta-findings:
+    - SECURITY-IAM_Use:
+        status: ACCEPTED_RISK
+        reason: We use Federation # until the SSO migration
+    - SECURITY-MFA_on_Root_Account: ""`,
+			keys: map[string]map[string][]string{
+				"111111111111": {findingTypeTrustedAdvisor: {"SECURITY-MFA_on_Root_Account", "FAULT_TOLERANCE-Amazon_EBS_Snapshots"}},
+				"222222222222": {findingTypeAWSHealth: {"AWS_EC2_OPERATIONAL_ISSUE"}},
+			},
+			expectedOutput: `# exceptions reviewed by the security team
+- accountid: "*"
+  ta-findings:
+  - SECURITY-*: '**EXCEPTION:** Security is handled by the security team'
+- accountid: [111111111111, 444444444444]
+  ta-findings:
+    - SECURITY-IAM_Use:
+        status: ACCEPTED_RISK
+        reason: We use Federation # until the SSO migration
+    - SECURITY-MFA_on_Root_Account: ""
+- accountid: "111111111111"
+  ta-findings:
+  - FAULT_TOLERANCE-Amazon_EBS_Snapshots: ""
+- accountid: "222222222222"
+  health-findings:
+  - AWS_EC2_OPERATIONAL_ISSUE: ""
+`,
+			expectedAdded: 2,
+		},
+		{
+			name:    "Return the content as is when every key has a comment",
+			content: "- accountid: \"111111111111\" # prod\n  ta-findings:\n  - SECURITY-IAM_Use: \"\"\n",
+			keys: map[string]map[string][]string{
+				"111111111111": {findingTypeTrustedAdvisor: {"SECURITY-IAM_Use"}},
+			},
+			expectedOutput: "- accountid: \"111111111111\" # prod\n  ta-findings:\n  - SECURITY-IAM_Use: \"\"\n",
+		},
+		{
+			name: "Return error for a section that is not a list",
+			content: `- accountid: "111111111111"
+  ta-findings: "none"
+`,
+			keys: map[string]map[string][]string{
+				"111111111111": {findingTypeTrustedAdvisor: {"SECURITY-IAM_Use"}},
+			},
+			expectedError: errors.New("unable to parse comments: yaml: unmarshal errors:\n  line 2: cannot unmarshal !!str `none` into []map[string]cloudig.Comment"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			keys := NewCommentKeys()
+			for accountID, sections := range tc.keys {
+				for section, sectionKeys := range sections {
+					for _, key := range sectionKeys {
+						keys.record(accountID, section, key, true)
+					}
+				}
+			}
+			output, added, err := ScaffoldComments([]byte(tc.content), keys)
+			assert.Equal(t, tc.expectedError, err)
+			assert.Equal(t, tc.expectedOutput, string(output))
+			assert.Equal(t, tc.expectedAdded, added)
+			if err == nil {
+				// the scaffolded file parses, with the new keys still new findings
+				var comments []Comments
+				assert.NoError(t, yaml.Unmarshal(output, &comments))
+			}
+		})
+	}
+}
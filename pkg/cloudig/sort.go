@@ -0,0 +1,334 @@
+package cloudig
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Keys the rows of the table outputs can be sorted and grouped by
+const (
+	TableKeyAccount  string = "account"
+	TableKeyCategory string = "category"
+	TableKeySeverity string = "severity"
+	TableKeyRegion   string = "region"
+)
+
+var tableKeys = []string{TableKeyAccount, TableKeyCategory, TableKeySeverity, TableKeyRegion}
+
+// severityAliases rank the Trusted Advisor statuses along with the ECR severities
+var severityAliases = map[string]string{"ERROR": "HIGH", "WARNING": "MEDIUM"}
+
+// tableSorter is implemented by the reports whose table rows can be sorted and grouped
+type tableSorter interface {
+	// rowKeys returns the value of a key for each row of the table output, "" when the key doesn't apply to the report
+	rowKeys(key string) []string
+	// withRows returns a copy of the report with the findings of the rows at the indexes, in order
+	withRows(indexes []int) Report
+}
+
+// tableGroup is the rows of a report with the same value of the group key
+type tableGroup struct {
+	value  string
+	report Report
+}
+
+func (report *TrustedAdvisorReport) rowKeys(key string) []string {
+	keys := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		keys = append(keys, map[string]string{TableKeyAccount: finding.AccountID, TableKeyCategory: finding.Category, TableKeySeverity: strings.ToUpper(finding.Status)}[key])
+	}
+	return keys
+}
+
+func (report *TrustedAdvisorReport) withRows(indexes []int) Report {
+	r := *report
+	r.Findings = make([]TrustedAdvisorFinding, 0, len(indexes))
+	for _, i := range indexes {
+		r.Findings = append(r.Findings, report.Findings[i])
+	}
+	return &r
+}
+
+// rowKeys of the Config report use the resource types of a finding as category
+func (report *ConfigReport) rowKeys(key string) []string {
+	keys := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		resourceTypes := make([]string, 0, len(finding.FlaggedResources))
+		for resourceType := range finding.FlaggedResources {
+			resourceTypes = append(resourceTypes, resourceType)
+		}
+		sort.Strings(resourceTypes)
+		keys = append(keys, map[string]string{TableKeyAccount: finding.AccountID, TableKeyCategory: strings.Join(resourceTypes, ","), TableKeyRegion: finding.Region}[key])
+	}
+	return keys
+}
+
+func (report *ConfigReport) withRows(indexes []int) Report {
+	r := *report
+	r.Findings = make([]ConfigFinding, 0, len(indexes))
+	for _, i := range indexes {
+		r.Findings = append(r.Findings, report.Findings[i])
+	}
+	return &r
+}
+
+// inspectorRow is a rule package of an assessment in the table output, finding is -1 for an assessment without findings
+// so that its AMIs are still output
+type inspectorRow struct {
+	report  int
+	finding int
+}
+
+func (reports *InspectorReports) rows() []inspectorRow {
+	rows := make([]inspectorRow, 0)
+	for i, report := range reports.Reports {
+		if len(report.Findings) == 0 {
+			rows = append(rows, inspectorRow{report: i, finding: -1})
+		}
+		for j := range report.Findings {
+			rows = append(rows, inspectorRow{report: i, finding: j})
+		}
+	}
+	return rows
+}
+
+// rowKeys of the Inspector report have a row per rule package of each assessment, with the rule package as category
+// and its most severe non-zero count as severity. An assessment without findings has a row without category and
+// severity
+func (reports *InspectorReports) rowKeys(key string) []string {
+	keys := make([]string, 0)
+	for _, row := range reports.rows() {
+		report := reports.Reports[row.report]
+		category, severity := "", ""
+		if row.finding >= 0 {
+			finding := report.Findings[row.finding]
+			category = finding.RulePackageName
+			for _, s := range inspectorSeverities {
+				if getInspectorSeverityCount(finding, s) != 0 {
+					severity = strings.ToUpper(s)
+					break
+				}
+			}
+		}
+		keys = append(keys, map[string]string{TableKeyAccount: report.AccountID, TableKeyCategory: category, TableKeySeverity: severity, TableKeyRegion: report.Region}[key])
+	}
+	return keys
+}
+
+// withRows keeps the rule packages of the rows in their assessment. Consecutive rows of the same assessment share it
+func (reports *InspectorReports) withRows(indexes []int) Report {
+	rows := reports.rows()
+	r := *reports
+	r.Reports = make([]InspectorReport, 0)
+	previous := -1
+	for _, i := range indexes {
+		if rows[i].report != previous {
+			report := reports.Reports[rows[i].report]
+			report.Findings = make([]InspectorReportFinding, 0)
+			r.Reports = append(r.Reports, report)
+			previous = rows[i].report
+		}
+		if rows[i].finding >= 0 {
+			last := &r.Reports[len(r.Reports)-1]
+			last.Findings = append(last.Findings, reports.Reports[rows[i].report].Findings[rows[i].finding])
+		}
+	}
+	return &r
+}
+
+// rowKeys of the Health report use the event type code as category
+func (report *HealthReport) rowKeys(key string) []string {
+	keys := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		keys = append(keys, map[string]string{TableKeyAccount: finding.AccountID, TableKeyCategory: finding.EventTypeCode, TableKeyRegion: finding.Region}[key])
+	}
+	return keys
+}
+
+func (report *HealthReport) withRows(indexes []int) Report {
+	r := *report
+	r.Findings = make([]HealthReportFinding, 0, len(indexes))
+	for _, i := range indexes {
+		r.Findings = append(r.Findings, report.Findings[i])
+	}
+	return &r
+}
+
+// rowKeys of the ECR scan report use the repository as category and the most severe severity of an image as severity
+func (report *ImageScanReports) rowKeys(key string) []string {
+	keys := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		severity := ""
+		for _, s := range getImageSeverities(finding) {
+			if finding.ImageFindingsCount[s] != 0 {
+				severity = s
+				break
+			}
+		}
+		keys = append(keys, map[string]string{TableKeyAccount: finding.AccountID, TableKeyCategory: finding.RepositoryName, TableKeySeverity: severity, TableKeyRegion: finding.Region}[key])
+	}
+	return keys
+}
+
+func (report *ImageScanReports) withRows(indexes []int) Report {
+	r := *report
+	r.Findings = make([]ImageScanFindings, 0, len(indexes))
+	for _, i := range indexes {
+		r.Findings = append(r.Findings, report.Findings[i])
+	}
+	return &r
+}
+
+func (report *ReflectReport) rowKeys(key string) []string {
+	keys := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		keys = append(keys, map[string]string{TableKeyAccount: finding.AccountID, TableKeyRegion: finding.Region}[key])
+	}
+	return keys
+}
+
+func (report *ReflectReport) withRows(indexes []int) Report {
+	r := *report
+	r.Findings = make([]ReflectFinding, 0, len(indexes))
+	for _, i := range indexes {
+		r.Findings = append(r.Findings, report.Findings[i])
+	}
+	return &r
+}
+
+// checkTableKey returns an error when a key is not a key the table rows can be sorted and grouped by
+func checkTableKey(key string) error {
+	if !Contains(tableKeys, key) {
+		return fmt.Errorf("unknown sort or group key '%s'. Options: [%s]", key, strings.Join(tableKeys, ", "))
+	}
+	return nil
+}
+
+// getSeverityRank ranks a severity from the most severe, case insensitively: the ECR severities rank the Inspector
+// severities and the Trusted Advisor statuses as well. Unknown severities rank last
+func getSeverityRank(severity string) int {
+	severity = strings.ToUpper(severity)
+	if alias, ok := severityAliases[severity]; ok {
+		severity = alias
+	}
+	for i, s := range ecrSeverities {
+		if s == severity {
+			return i
+		}
+	}
+	return len(ecrSeverities)
+}
+
+// compareTableKey compares two values of a key. Severities compare from the most severe, and rows without a severity
+// come last
+func compareTableKey(key string, a string, b string) int {
+	if key == TableKeySeverity && getSeverityRank(a) != getSeverityRank(b) {
+		if getSeverityRank(a) < getSeverityRank(b) {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(a, b)
+}
+
+// sortTableRows returns a copy of the report with its rows sorted by the keys, in order. Rows with the same values keep
+// their order, which is the account ID and region they were collected in. Every report of a composite is sorted
+func sortTableRows(report Report, keys []string) Report {
+	if composite, ok := report.(*CompositeReport); ok {
+		r := *composite
+		r.Reports = make([]Report, 0, len(composite.Reports))
+		for _, child := range composite.Reports {
+			r.Reports = append(r.Reports, sortTableRows(child, keys))
+		}
+		return &r
+	}
+	sorter, ok := report.(tableSorter)
+	if !ok || len(keys) == 0 {
+		return report
+	}
+	values := make([][]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, sorter.rowKeys(key))
+	}
+	indexes := make([]int, len(values[0]))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		for k, key := range keys {
+			if c := compareTableKey(key, values[k][indexes[i]], values[k][indexes[j]]); c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+	return sorter.withRows(indexes)
+}
+
+// groupTableRows splits the rows of a report by the value of the key, sorted by value. A report the key doesn't apply
+// to is a single group without value
+func groupTableRows(report Report, key string) []tableGroup {
+	sorter, ok := report.(tableSorter)
+	if !ok {
+		return []tableGroup{{report: report}}
+	}
+	groups := make([]tableGroup, 0)
+	rows := make(map[string][]int)
+	for i, value := range sorter.rowKeys(key) {
+		if _, ok := rows[value]; !ok {
+			groups = append(groups, tableGroup{value: value})
+		}
+		rows[value] = append(rows[value], i)
+	}
+	if len(groups) == 0 {
+		return []tableGroup{{report: report}}
+	}
+	sort.SliceStable(groups, func(i, j int) bool { return compareTableKey(key, groups[i].value, groups[j].value) < 0 })
+	for i := range groups {
+		groups[i].report = sorter.withRows(rows[groups[i].value])
+	}
+	return groups
+}
+
+// toGroupedTable outputs a table per value of the group key, each under a heading with the value. Every report of a
+// composite is grouped under its own heading
+func toGroupedTable(report Report, tableType string, groupBy string) string {
+	var tables strings.Builder
+	if composite, ok := report.(*CompositeReport); ok {
+		for _, child := range composite.Reports {
+			tables.WriteString(getTableTitle(tableType, getReportTitle(child), 2))
+			tables.WriteString(toGroupedTable(child, tableType, groupBy) + "\n")
+		}
+		return tables.String()
+	}
+	groups := groupTableRows(report, groupBy)
+	if len(groups) == 1 && groups[0].value == "" {
+		return renderTable(groups[0].report, tableType)
+	}
+	for i, group := range groups {
+		value := group.value
+		if value == "" {
+			value = "none"
+		}
+		tables.WriteString(getTableTitle(tableType, groupBy+": "+value, 3))
+		tables.WriteString(renderTable(group.report, tableType))
+		if i < len(groups)-1 {
+			tables.WriteString("\n")
+		}
+	}
+	return tables.String()
+}
+
+// getTableTitle returns the heading of a table: a markdown heading of the level for mdtable, and the title underlined
+// with "=" for level 2 and "-" for the levels below otherwise
+func getTableTitle(tableType string, title string, level int) string {
+	if tableType == tableTypeMD {
+		return strings.Repeat("#", level) + " " + title + "\n\n"
+	}
+	underline := "-"
+	if level <= 2 {
+		underline = "="
+	}
+	return title + "\n" + strings.Repeat(underline, len(title)) + "\n"
+}
@@ -0,0 +1,122 @@
+package cloudig
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSortTableRows(t *testing.T) {
+	report := &ImageScanReports{Findings: []ImageScanFindings{
+		{AccountID: "111111111111", Region: "us-east-1", RepositoryName: "web", ImageFindingsCount: map[string]int64{"LOW": 1}},
+		{AccountID: "111111111111", Region: "us-west-2", RepositoryName: "api", ImageFindingsCount: map[string]int64{"CRITICAL": 1, "LOW": 3}},
+		{AccountID: "222222222222", Region: "us-east-1", RepositoryName: "api", ImageFindingsCount: map[string]int64{"HIGH": 2}},
+		{AccountID: "222222222222", Region: "us-east-1", RepositoryName: "web", ImageFindingsCount: map[string]int64{"CRITICAL": 0, "MEDIUM": 1}},
+	}}
+	testCases := []struct {
+		name          string
+		keys          []string
+		expectedRepos []string
+	}{
+		{
+			name:          "Keep the collection order without keys",
+			keys:          []string{},
+			expectedRepos: []string{"111111111111/us-east-1/web", "111111111111/us-west-2/api", "222222222222/us-east-1/api", "222222222222/us-east-1/web"},
+		},
+		{
+			name:          "Sort by the most severe non-zero count",
+			keys:          []string{TableKeySeverity},
+			expectedRepos: []string{"111111111111/us-west-2/api", "222222222222/us-east-1/api", "222222222222/us-east-1/web", "111111111111/us-east-1/web"},
+		},
+		{
+			name:          "Sort by region then category",
+			keys:          []string{TableKeyRegion, TableKeyCategory},
+			expectedRepos: []string{"222222222222/us-east-1/api", "111111111111/us-east-1/web", "222222222222/us-east-1/web", "111111111111/us-west-2/api"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repos := make([]string, 0)
+			for _, finding := range sortTableRows(report, tc.keys).(*ImageScanReports).Findings {
+				repos = append(repos, finding.AccountID+"/"+finding.Region+"/"+finding.RepositoryName)
+			}
+			assert.Equal(t, tc.expectedRepos, repos)
+		})
+	}
+	// the report itself is left as is
+	assert.Equal(t, "web", report.Findings[0].RepositoryName)
+
+	// the account and region of a row are only left blank when they repeat the previous row
+	output := renderTable(sortTableRows(report, []string{TableKeySeverity}), tableTypeMD)
+	assert.Equal(t, 2, strings.Count(output, "111111111111"))
+	assert.Equal(t, 1, strings.Count(output, "222222222222"))
+	assert.Equal(t, 3, strings.Count(output, "us-west-2")+strings.Count(output, "us-east-1"))
+}
+
+func TestSortTableRowsInspector(t *testing.T) {
+	reports := &InspectorReports{Reports: []InspectorReport{
+		{AccountID: "111111111111", TemplateName: "weekly", AMI: map[string]int{"ami-1": 10}, Findings: []InspectorReportFinding{
+			{RulePackageName: "CVE", High: "0", Medium: "3", Low: "0", Informational: "0"},
+			{RulePackageName: "CIS", High: "2", Medium: "0", Low: "0", Informational: "0"},
+		}},
+		{AccountID: "222222222222", TemplateName: "weekly", Findings: []InspectorReportFinding{
+			{RulePackageName: "CVE", High: "1", Medium: "0", Low: "0", Informational: "0"},
+		}},
+	}}
+
+	sorted := sortTableRows(reports, []string{TableKeySeverity}).(*InspectorReports)
+	assert.Equal(t, []InspectorReport{
+		{AccountID: "111111111111", TemplateName: "weekly", AMI: map[string]int{"ami-1": 10}, Findings: []InspectorReportFinding{
+			{RulePackageName: "CIS", High: "2", Medium: "0", Low: "0", Informational: "0"},
+		}},
+		{AccountID: "222222222222", TemplateName: "weekly", Findings: []InspectorReportFinding{
+			{RulePackageName: "CVE", High: "1", Medium: "0", Low: "0", Informational: "0"},
+		}},
+		{AccountID: "111111111111", TemplateName: "weekly", AMI: map[string]int{"ami-1": 10}, Findings: []InspectorReportFinding{
+			{RulePackageName: "CVE", High: "0", Medium: "3", Low: "0", Informational: "0"},
+		}},
+	}, sorted.Reports)
+	// the AMIs of an assessment split by the sort are listed once
+	assert.Equal(t, 1, strings.Count(sorted.toTable(tableTypeMD), "ami-1"))
+
+	// an assessment without findings keeps its AMIs when sorted and grouped
+	reports.Reports = append(reports.Reports, InspectorReport{AccountID: "333333333333", TemplateName: "weekly", AMI: map[string]int{"ami-3": 5}, Findings: []InspectorReportFinding{}})
+	assert.Contains(t, renderTable(sortTableRows(reports, []string{TableKeySeverity}), tableTypeMD), "ami-3")
+	assert.Contains(t, toGroupedTable(reports, tableTypeMD, TableKeyCategory), "ami-3")
+}
+
+func TestGroupedTableOutput(t *testing.T) {
+	report := &TrustedAdvisorReport{Findings: []TrustedAdvisorFinding{
+		{AccountID: "222222222222", Category: "SECURITY", Name: "MFA on Root Account", Status: "error", Comments: "NEW_FINDING"},
+		{AccountID: "111111111111", Category: "COST_OPTIMIZING", Name: "Idle Load Balancers", Status: "warning", Comments: "NEW_FINDING"},
+		{AccountID: "111111111111", Category: "SECURITY", Name: "IAM Use", Status: "warning", Comments: "NEW_FINDING"},
+	}}
+
+	output := toGroupedTable(report, tableTypeMD, TableKeyAccount)
+	assert.Equal(t, 2, strings.Count(output, "ACCOUNT ID"))
+	first, second := strings.Index(output, "### account: 111111111111\n\n"), strings.Index(output, "### account: 222222222222\n\n")
+	assert.True(t, first == 0 && second > first)
+	assert.True(t, strings.Index(output, "IAM Use") < second && strings.Index(output, "Root Account") > second)
+
+	output = toGroupedTable(report, tableTypeNormal, TableKeySeverity)
+	assert.True(t, strings.HasPrefix(output, "severity: ERROR\n---------------\n"))
+	assert.Contains(t, output, "\nseverity: WARNING\n-----------------\n")
+
+	// the Health report has no severity, its rows are a single table without heading
+	health := &HealthReport{Findings: []HealthReportFinding{{AccountID: "111111111111", EventTypeCode: "AWS_EC2_MAINTENANCE_SCHEDULED"}}}
+	assert.Equal(t, health.toTable(tableTypeMD), toGroupedTable(health, tableTypeMD, TableKeySeverity))
+
+	// each report of a composite is grouped under its own heading
+	output = toGroupedTable(&CompositeReport{Reports: []Report{report, health}}, tableTypeMD, TableKeyAccount)
+	assert.True(t, strings.HasPrefix(output, "## "+getReportTitle(report)+"\n\n### account: 111111111111\n\n"))
+	assert.Contains(t, output, "## "+getReportTitle(health)+"\n\n### account: 111111111111\n\n")
+}
+
+func TestCheckOutputOptionsTableKeys(t *testing.T) {
+	assert.NoError(t, CheckOutputOptions(&HealthReport{}, OutputOptions{Type: OutputTypeMDTable, SortBy: []string{TableKeyRegion, TableKeyAccount}, GroupBy: TableKeySeverity}))
+	assert.Equal(t, errors.New("unknown sort or group key 'owner'. Options: [account, category, severity, region]"), CheckOutputOptions(&HealthReport{}, OutputOptions{Type: OutputTypeTable, SortBy: []string{"owner"}}))
+	assert.Equal(t, errors.New("sorting and grouping the rows is only supported by the table and mdtable outputs"), CheckOutputOptions(&HealthReport{}, OutputOptions{Type: OutputTypeJSON, GroupBy: TableKeyAccount}))
+}
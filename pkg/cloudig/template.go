@@ -0,0 +1,126 @@
+package cloudig
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// OutputTypeTemplatePrefix prefixes the path of the text/template file of the template output, ex: "template=./my.tmpl"
+const OutputTypeTemplatePrefix string = "template="
+
+// markdownEscaper escapes the characters with a meaning in markdown, and the line breaks so that a value fits in a
+// table cell
+var markdownEscaper = strings.NewReplacer(`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`, "<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`, "\r\n", "<br>", "\n", "<br>")
+
+// templateFuncs are the functions of the output templates besides the text/template builtins
+var templateFuncs = template.FuncMap{
+	"join":           templateJoin,
+	"sortSeverities": templateSortSeverities,
+	"mdEscape":       markdownEscaper.Replace,
+	"groupByAccount": templateGroupByAccount,
+}
+
+// templateSeverity is a severity and its count of findings, ex: an entry of imageFindingsCount
+type templateSeverity struct {
+	Severity string
+	Count    interface{}
+}
+
+// templateAccount is the findings of an account
+type templateAccount struct {
+	AccountID string
+	Findings  []interface{}
+}
+
+// loadOutputTemplate parses the template file of a template output type
+func loadOutputTemplate(outputType string) (*template.Template, error) {
+	path := strings.TrimPrefix(outputType, OutputTypeTemplatePrefix)
+	if path == "" {
+		return nil, fmt.Errorf("missing template file, use %s<path>", OutputTypeTemplatePrefix)
+	}
+	t, err := template.New(filepath.Base(path)).Funcs(templateFuncs).ParseFiles(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the output template: %v", err)
+	}
+	return t, nil
+}
+
+// toTemplate renders a report through the template of the output type. The data of the template is the JSON output
+// of the report, with the JSON field names, ex: {{range .findings}}{{.accountId}}{{end}}
+func toTemplate(report Report, outputType string, reportErrors []ReportError) (string, error) {
+	t, err := loadOutputTemplate(outputType)
+	if err != nil {
+		return "", err
+	}
+	report.setErrors(reportErrors)
+	var data interface{}
+	if err := json.Unmarshal([]byte(report.toJSON(&report)), &data); err != nil {
+		return "", fmt.Errorf("unable to read the JSON output of the report: %v", err)
+	}
+	var output strings.Builder
+	if err := t.Execute(&output, data); err != nil {
+		return "", fmt.Errorf("unable to render the output template: %v", err)
+	}
+	return output.String(), nil
+}
+
+// templateJoin joins the values of a list with a separator, ex: {{.flaggedResources | join ", "}}
+func templateJoin(sep string, values interface{}) string {
+	switch v := values.(type) {
+	case []string:
+		return strings.Join(v, sep)
+	case []interface{}:
+		s := make([]string, 0, len(v))
+		for _, value := range v {
+			s = append(s, fmt.Sprint(value))
+		}
+		return strings.Join(s, sep)
+	case nil:
+		return ""
+	}
+	return fmt.Sprint(values)
+}
+
+// templateSortSeverities returns the severities of a map of counts by severity from the most severe, ex:
+// {{range sortSeverities .imageFindingsCount}}{{.Severity}}: {{.Count}}{{end}}. Unknown severities come last, sorted
+// by name
+func templateSortSeverities(counts map[string]interface{}) []templateSeverity {
+	severities := make([]templateSeverity, 0, len(counts))
+	for severity, count := range counts {
+		severities = append(severities, templateSeverity{Severity: severity, Count: count})
+	}
+	sort.Slice(severities, func(i, j int) bool {
+		ri, rj := getSeverityRank(severities[i].Severity), getSeverityRank(severities[j].Severity)
+		if ri != rj {
+			return ri < rj
+		}
+		return severities[i].Severity < severities[j].Severity
+	})
+	return severities
+}
+
+// templateGroupByAccount groups a list of findings by their accountId, sorted by account, ex:
+// {{range groupByAccount .findings}}{{.AccountID}}: {{len .Findings}}{{end}}
+func templateGroupByAccount(findings []interface{}) []templateAccount {
+	accounts := make([]templateAccount, 0)
+	index := make(map[string]int)
+	for _, finding := range findings {
+		accountID := ""
+		if m, ok := finding.(map[string]interface{}); ok {
+			accountID, _ = m["accountId"].(string)
+		}
+		i, ok := index[accountID]
+		if !ok {
+			i = len(accounts)
+			index[accountID] = i
+			accounts = append(accounts, templateAccount{AccountID: accountID})
+		}
+		accounts[i].Findings = append(accounts[i].Findings, finding)
+	}
+	sort.SliceStable(accounts, func(i, j int) bool { return accounts[i].AccountID < accounts[j].AccountID })
+	return accounts
+}
@@ -0,0 +1,68 @@
+package cloudig
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateOutput(t *testing.T) {
+	report := &ImageScanReports{Findings: []ImageScanFindings{
+		{AccountID: "222222222222", Region: "us-east-1", RepositoryName: "app/api", ImageTag: "v2", ImageFindingsCount: map[string]int64{"LOW": 1, "CRITICAL": 2, "HIGH": 3}, Comments: "NEW_FINDING"},
+		{AccountID: "111111111111", Region: "us-east-1", RepositoryName: "app/web", ImageTag: "v1,latest", ImageFindingsCount: map[string]int64{"MEDIUM": 4}, Comments: "**EXCEPTION:** Base_image | patched"},
+	}}
+	reportErrors := []ReportError{{Account: "arn:aws:iam::333333333333:role/cloudig", AccountID: "333333333333", Message: "AccessDenied"}}
+
+	output, err := RenderReport(report, OutputTypeTemplatePrefix+"../../test/data/report.tmpl", reportErrors)
+	assert.NoError(t, err)
+	assert.Equal(t, `# ECR scan
+
+## 111111111111
+
+* app/web:v1,latest MEDIUM=4 | \*\*EXCEPTION:\*\* Base\_image \| patched
+
+## 222222222222
+
+* app/api:v2 CRITICAL=2 HIGH=3 LOW=1 | NEW\_FINDING
+Error: arn:aws:iam::333333333333:role/cloudig AccessDenied
+`, output)
+
+	_, err = RenderReport(report, OutputTypeTemplatePrefix+"../../test/data/missing.tmpl", reportErrors)
+	assert.Equal(t, errors.New("unable to parse the output template: open ../../test/data/missing.tmpl: no such file or directory"), err)
+
+	// the errors of the template are returned rather than an empty output
+	path := filepath.Join(t.TempDir(), "broken.tmpl")
+	assert.NoError(t, ioutil.WriteFile(path, []byte("{{index .findings 5}}"), 0644))
+	err = OutputReport(nil, report, OutputOptions{Type: OutputTypeTemplatePrefix + path}, reportErrors)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "unable to render the output template")
+}
+
+func TestCheckOutputTypeTemplate(t *testing.T) {
+	assert.NoError(t, CheckOutputType(&HealthReport{}, OutputTypeTemplatePrefix+"../../test/data/report.tmpl"))
+	assert.Equal(t, errors.New("missing template file, use template=<path>"), CheckOutputType(&HealthReport{}, OutputTypeTemplatePrefix))
+	assert.Equal(t, errors.New("unable to parse the output template: open ../../test/data/missing.tmpl: no such file or directory"), CheckOutputType(&HealthReport{}, OutputTypeTemplatePrefix+"../../test/data/missing.tmpl"))
+}
+
+func TestTemplateFuncs(t *testing.T) {
+	assert.Equal(t, "a, b, 1", templateJoin(", ", []interface{}{"a", "b", 1.0}))
+	assert.Equal(t, "a|b", templateJoin("|", []string{"a", "b"}))
+	assert.Equal(t, "", templateJoin(", ", nil))
+
+	assert.Equal(t, []templateSeverity{{"High", "1"}, {"Low", "0"}, {"Informational", "3"}, {"OTHER", 2.0}}, templateSortSeverities(map[string]interface{}{"Informational": "3", "OTHER": 2.0, "Low": "0", "High": "1"}))
+
+	assert.Equal(t, "\\*\\*EXCEPTION:\\*\\* see \\[wiki\\]<br>\\#1 \\| \\`code\\`", markdownEscaper.Replace("**EXCEPTION:** see [wiki]\n#1 | `code`"))
+
+	findings := []interface{}{
+		map[string]interface{}{"accountId": "222222222222", "name": "a"},
+		map[string]interface{}{"accountId": "111111111111", "name": "b"},
+		map[string]interface{}{"accountId": "222222222222", "name": "c"},
+	}
+	assert.Equal(t, []templateAccount{
+		{AccountID: "111111111111", Findings: []interface{}{findings[1]}},
+		{AccountID: "222222222222", Findings: []interface{}{findings[0], findings[2]}},
+	}, templateGroupByAccount(findings))
+}
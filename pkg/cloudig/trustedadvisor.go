@@ -1,6 +1,8 @@
 package cloudig
 
 import (
+	"regexp"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,11 +14,12 @@ import (
 
 // TrustedAdvisorReport is struct that contains an array of Trusted Advisor findings
 type TrustedAdvisorReport struct {
-	Findings []trustedAdvisorFinding `json:"findings"`
+	Findings []TrustedAdvisorFinding `json:"findings"`
 	jsonOutputHelper
 }
 
-type trustedAdvisorFinding struct {
+// TrustedAdvisorFinding is a failing Trusted Advisor check with the resources it flags
+type TrustedAdvisorFinding struct {
 	AccountID        string                                 `json:"accountId"`
 	Category         string                                 `json:"category"`
 	Name             string                                 `json:"name"`
@@ -24,13 +27,39 @@ type trustedAdvisorFinding struct {
 	Status           string                                 `json:"status"`
 	ResourcesSummary support.TrustedAdvisorResourcesSummary `json:"resourcesSummary"` // map[string]int64
 	FlaggedResources []string                               `json:"flaggedResources"`
-	Comments         string                                 `json:"comments"`
+	// SuppressedResources are the comments of the flagged resources suppressed in the comments file by resource
+	SuppressedResources map[string]string `json:"suppressedResources,omitempty"`
+	Comments            string            `json:"comments"`
+	Owner               string            `json:"owner,omitempty"`
+	Ticket              string            `json:"ticket,omitempty"`
+}
+
+func init() {
+	RegisterReportType(ReportType{
+		Name:        "trustedadvisor",
+		Title:       "Trusted Advisor",
+		Command:     CommandGet,
+		Aliases:     []string{"ta", "t"},
+		Short:       "Get AWS Trusted Advisor report findings",
+		CommentsKey: findingTypeTrustedAdvisor,
+		// CATEGORY-Check_name, ex: SECURITY-IAM_Use
+		CommentsKeyFormat: regexp.MustCompile(`^[A-Z_]+-\S+$`),
+		Report:            &TrustedAdvisorReport{},
+		FailOnKeys:        []string{"error", "warning"},
+		CountFindings:     countTrustedAdvisorFindings,
+		Table: func(report Report, tableType string) string {
+			return report.(*TrustedAdvisorReport).toTable(tableType)
+		},
+		New: func(options ReportOptions) (Report, error) {
+			return &TrustedAdvisorReport{}, nil
+		},
+	})
 }
 
 // GetReport retrives the trusted advisor report for a given account,
 func (report *TrustedAdvisorReport) GetReport(client awslocal.APIs, comments []Comments) error {
 	start := time.Now()
-	finding := trustedAdvisorFinding{}
+	finding := TrustedAdvisorFinding{}
 
 	// Get accountID from roleARN
 	accountID, err := client.GetAccountID()
@@ -49,10 +78,34 @@ func (report *TrustedAdvisorReport) GetReport(client awslocal.APIs, comments []C
 	return nil
 }
 
-func processTrustedAdvisorResults(results map[*support.TrustedAdvisorCheckDescription]*support.TrustedAdvisorCheckResult, accountID string, comments []Comments) []trustedAdvisorFinding {
-	findings := make([]trustedAdvisorFinding, 0)
+func (report *TrustedAdvisorReport) newReport(region string) Report {
+	return &TrustedAdvisorReport{}
+}
+
+func (report *TrustedAdvisorReport) mergeReport(r Report) {
+	report.Findings = append(report.Findings, r.(*TrustedAdvisorReport).Findings...)
+}
+
+// Trusted Advisor checks cover all the regions of the account
+func (report *TrustedAdvisorReport) isRegional() bool {
+	return false
+}
+
+func (report *TrustedAdvisorReport) recordCommentKeys(keys *CommentKeys) {
+	for _, finding := range report.Findings {
+		keys.record(finding.AccountID, findingTypeTrustedAdvisor, trustedAdvisorCommentKey(finding.Category, finding.Name), finding.Comments == commentNewFinding)
+	}
+}
+
+// trustedAdvisorCommentKey returns the comments file key of a check, ex: SECURITY-IAM_Use
+func trustedAdvisorCommentKey(category string, name string) string {
+	return category + "-" + strings.Replace(name, " ", "_", -1)
+}
+
+func processTrustedAdvisorResults(results map[*support.TrustedAdvisorCheckDescription]*support.TrustedAdvisorCheckResult, accountID string, comments []Comments) []TrustedAdvisorFinding {
+	findings := make([]TrustedAdvisorFinding, 0)
 	for check, result := range results {
-		finding := trustedAdvisorFinding{
+		finding := TrustedAdvisorFinding{
 			AccountID:        accountID,
 			Category:         strings.ToUpper(aws.StringValue(check.Category)),
 			Name:             aws.StringValue(check.Name),
@@ -61,7 +114,8 @@ func processTrustedAdvisorResults(results map[*support.TrustedAdvisorCheckDescri
 			ResourcesSummary: *result.ResourcesSummary,
 			FlaggedResources: []string{},
 		}
-		finding.Comments = getComments(comments, finding.AccountID, findingTypeTrustedAdvisor, finding.Category+"-"+strings.Replace(finding.Name, " ", "_", -1))
+		comment := getComments(comments, finding.AccountID, findingTypeTrustedAdvisor, trustedAdvisorCommentKey(finding.Category, finding.Name))
+		finding.Comments, finding.Owner, finding.Ticket = comment.String(), comment.Owner, comment.Ticket
 		for _, resource := range result.FlaggedResources {
 			if resource.Metadata != nil {
 				if !awslocal.SdkStringContains(resource.Metadata, aws.String("Green")) && aws.BoolValue(resource.IsSuppressed) == false {
@@ -93,7 +147,18 @@ func processTrustedAdvisorResults(results map[*support.TrustedAdvisorCheckDescri
 				finding.FlaggedResources = append(finding.FlaggedResources, "NA")
 			}
 		}
+		finding.FlaggedResources, finding.SuppressedResources = comment.suppressResources(finding.FlaggedResources)
+		if len(finding.FlaggedResources) == 0 && len(finding.SuppressedResources) != 0 && isNewFinding(finding.Comments) {
+			finding.Comments = commentAllResourcesSuppressed
+		}
 		findings = append(findings, finding)
 	}
+	// the checks come from a map, sort them so that the output doesn't change from run to run
+	sort.Slice(findings, func(i, j int) bool {
+		if findings[i].Category != findings[j].Category {
+			return findings[i].Category < findings[j].Category
+		}
+		return findings[i].Name < findings[j].Name
+	})
 	return findings
 }
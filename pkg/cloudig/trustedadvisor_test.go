@@ -18,7 +18,7 @@ func TestTrustedAdvisorGetReport(t *testing.T) {
 		mockGetFailingTrustedAdvisorCheckResultsResponse map[*support.TrustedAdvisorCheckDescription]*support.TrustedAdvisorCheckResult
 		mockGetAccountIDError                            error
 		mockGetFailingTrustedAdvisorCheckResultsError    error
-		expectedFindings                                 []trustedAdvisorFinding
+		expectedFindings                                 []TrustedAdvisorFinding
 		expectedError                                    error
 	}{
 		{
@@ -156,7 +156,7 @@ func TestTrustedAdvisorGetReport(t *testing.T) {
 					},
 				},
 			},
-			expectedFindings: []trustedAdvisorFinding{
+			expectedFindings: []TrustedAdvisorFinding{
 				{
 					AccountID:   "111111111111",
 					Category:    "COST_OPTIMIZING",
@@ -211,7 +211,7 @@ func TestTrustedAdvisorGetReport(t *testing.T) {
 			mockAPIs.EXPECT().GetAccountID().Return(tc.accountID, tc.mockGetAccountIDError).MaxTimes(1)
 			mockAPIs.EXPECT().GetFailingTrustedAdvisorCheckResults().Return(tc.mockGetFailingTrustedAdvisorCheckResultsResponse, tc.mockGetFailingTrustedAdvisorCheckResultsError).MaxTimes(1)
 
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 			report := &TrustedAdvisorReport{}
 			err := report.GetReport(mockAPIs, comments)
 
@@ -226,7 +226,7 @@ func TestProcessTrustedAdvisorResults(t *testing.T) {
 		name           string
 		results        map[*support.TrustedAdvisorCheckDescription]*support.TrustedAdvisorCheckResult
 		account        string
-		expectedOutput []trustedAdvisorFinding
+		expectedOutput []TrustedAdvisorFinding
 	}{
 		{
 			name: "Return expected results",
@@ -363,7 +363,7 @@ func TestProcessTrustedAdvisorResults(t *testing.T) {
 				},
 			},
 			account: "111111111111",
-			expectedOutput: []trustedAdvisorFinding{
+			expectedOutput: []TrustedAdvisorFinding{
 				{
 					AccountID:   "111111111111",
 					Category:    "COST_OPTIMIZING",
@@ -399,9 +399,9 @@ func TestProcessTrustedAdvisorResults(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// Use comments file for testing
-			comments := parseCommentsFile("../../test/data/comments.yaml")
+			comments := ParseCommentsFile("../../test/data/comments.yaml")
 			output := processTrustedAdvisorResults(tc.results, tc.account, comments)
-			assert.ElementsMatch(t, tc.expectedOutput, output)
+			assert.Equal(t, tc.expectedOutput, output)
 		})
 	}
 }
@@ -1,5 +1,7 @@
 package cloudig
 
+import "strings"
+
 // Contains tells whether slice of strings 'ss' contains string 's'.
 func Contains(ss []string, s string) bool {
 	for _, n := range ss {
@@ -29,3 +31,15 @@ func ContainsKey(sm []map[string]string, key string) string {
 	}
 	return "NEW_FINDING"
 }
+
+// ParseTags converts string "k1:v1,k2:v2" to map[string]string{"k1":"v1","k2":"v2"}
+func ParseTags(tagString string) map[string]string {
+	tags := make(map[string]string)
+	for _, v := range strings.Split(tagString, ",") {
+		kv := strings.Split(v, ":")
+		if len(kv) == 2 {
+			tags[kv[0]] = kv[1]
+		}
+	}
+	return tags
+}
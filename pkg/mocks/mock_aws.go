@@ -83,6 +83,21 @@ func (mr *MockAPIsMockRecorder) GetAccountID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAccountID", reflect.TypeOf((*MockAPIs)(nil).GetAccountID))
 }
 
+// GetActiveAccounts mocks base method
+func (m *MockAPIs) GetActiveAccounts(arg0 []string, arg1 map[string]string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveAccounts", arg0, arg1)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveAccounts indicates an expected call of GetActiveAccounts
+func (mr *MockAPIsMockRecorder) GetActiveAccounts(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveAccounts", reflect.TypeOf((*MockAPIs)(nil).GetActiveAccounts), arg0, arg1)
+}
+
 // GetECRImagesWithTag mocks base method
 func (m *MockAPIs) GetECRImagesWithTag(arg0 string) (map[string][]*ecr.ImageDetail, error) {
 	m.ctrl.T.Helper()